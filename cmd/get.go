@@ -0,0 +1,300 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bytedance/sonic"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/extractors"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/networking"
+	"github.com/govdbot/govd/internal/plugins"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const getUsage = `usage: govd get [flags] <url>...
+
+downloads the media of the urls to a local directory,
+without telegram and the database.
+
+flags:
+`
+
+type getOptions struct {
+	output      string
+	format      string
+	audioOnly   bool
+	json        bool
+	listFormats bool
+	record      string
+	replay      string
+}
+
+// the result of a url, printed with --json
+type getResult struct {
+	URL         string       `json:"url"`
+	ExtractorID string       `json:"extractor_id,omitempty"`
+	ContentID   string       `json:"content_id,omitempty"`
+	Caption     string       `json:"caption,omitempty"`
+	Items       []*getItem   `json:"items,omitempty"`
+	Entries     []*getResult `json:"entries,omitempty"`
+	Files       []string     `json:"files,omitempty"`
+	Error       string       `json:"error,omitempty"`
+
+	media *models.Media
+}
+
+type getItem struct {
+	Formats []*getFormat `json:"formats"`
+}
+
+type getFormat struct {
+	FormatID   string              `json:"format_id"`
+	Type       database.MediaType  `json:"type"`
+	VideoCodec database.MediaCodec `json:"video_codec,omitempty"`
+	AudioCodec database.MediaCodec `json:"audio_codec,omitempty"`
+	FileSize   int64               `json:"file_size,omitempty"`
+	Duration   int32               `json:"duration,omitempty"`
+	Width      int32               `json:"width,omitempty"`
+	Height     int32               `json:"height,omitempty"`
+	Bitrate    int64               `json:"bitrate,omitempty"`
+	Title      string              `json:"title,omitempty"`
+	Artist     string              `json:"artist,omitempty"`
+	URL        []string            `json:"url,omitempty"`
+}
+
+// runs the get subcommand, returning the exit code
+func runGet(args []string) int {
+	var opts getOptions
+
+	flags := flag.NewFlagSet("get", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), getUsage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&opts.output, "output", ".", "directory the files are saved to")
+	flags.StringVar(&opts.format, "format", "", "id of the format to download, as shown by --list-formats")
+	flags.BoolVar(&opts.audioOnly, "audio", false, "download only the audio")
+	flags.BoolVar(&opts.json, "json", false, "print the metadata as json")
+	flags.BoolVar(&opts.listFormats, "list-formats", false, "list the formats without downloading them")
+	flags.StringVar(&opts.record, "record", "", "record the http requests to the cassettes directory")
+	flags.StringVar(&opts.replay, "replay", "", "serve the http requests from the cassettes directory, offline")
+	flags.Parse(args)
+
+	urls := flags.Args()
+	if len(urls) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	logger.InitStderr()
+	defer logger.L.Sync()
+
+	config.LoadStandalone()
+	logger.SetLevel(config.Env.LogLevel)
+	plugins.Load()
+	localization.Init()
+
+	switch {
+	case opts.record != "" && opts.replay != "":
+		logger.L.Error("--record and --replay can't be used together")
+		return 2
+	case opts.record != "":
+		config.Env.CassetteMode = string(networking.CassetteModeRecord)
+		config.Env.CassetteDir = opts.record
+	case opts.replay != "":
+		config.Env.CassetteMode = string(networking.CassetteModeReplay)
+		config.Env.CassetteDir = opts.replay
+	}
+
+	if !opts.listFormats && !util.CheckFFmpeg() {
+		logger.L.Error("ffmpeg binary not found in PATH")
+		return 1
+	}
+
+	if err := os.MkdirAll(opts.output, 0755); err != nil {
+		logger.L.Errorf("failed to create output directory: %v", err)
+		return 1
+	}
+	// temporary files are kept apart from the output,
+	// only the final files are moved there
+	tempDir, err := os.MkdirTemp(opts.output, ".govd-")
+	if err != nil {
+		logger.L.Errorf("failed to create temporary directory: %v", err)
+		return 1
+	}
+	defer os.RemoveAll(tempDir)
+	config.Env.DownloadsDirectory = tempDir
+
+	exitCode := 0
+	results := make([]*getResult, 0, len(urls))
+	for _, url := range urls {
+		result := getURL(url, &opts, true)
+		if hasError(result) {
+			exitCode = 1
+		}
+		results = append(results, result)
+		if !opts.json {
+			printResult(result, &opts, "")
+		}
+	}
+
+	if opts.json {
+		encoder := sonic.ConfigDefault.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			logger.L.Errorf("failed to encode results: %v", err)
+			return 1
+		}
+	}
+	return exitCode
+}
+
+// extracts and downloads the url. the entries of collections
+// are downloaded too, unless they are collections themselves.
+func getURL(url string, opts *getOptions, expand bool) *getResult {
+	result := &getResult{URL: url}
+
+	extractorCtx := extractors.FromURL(url)
+	if extractorCtx == nil || extractorCtx.Extractor == nil {
+		result.Error = "unsupported url"
+		return result
+	}
+	defer extractorCtx.CancelFunc()
+	defer extractorCtx.FilesTracker.Cleanup()
+
+	extractorCtx.FormatID = opts.format
+	extractorCtx.AudioOnly = opts.audioOnly
+	extractorCtx.Standalone = true
+
+	result.ExtractorID = extractorCtx.Extractor.ID
+	result.ContentID = extractorCtx.ContentID
+
+	resp, err := extractorCtx.Extractor.GetFunc(extractorCtx)
+	if err != nil {
+		result.Error = errorMessage(err)
+		return result
+	}
+
+	if len(resp.Entries) > 0 {
+		if !expand {
+			result.Error = errorMessage(util.ErrCollectionUnsupported)
+			return result
+		}
+		for _, entry := range resp.Entries {
+			result.Entries = append(result.Entries, getURL(entry, opts, false))
+		}
+		return result
+	}
+	if resp.Media == nil || len(resp.Media.Items) == 0 {
+		result.Error = errorMessage(core.ErrNoMedia)
+		return result
+	}
+
+	result.media = resp.Media
+	result.Caption = resp.Media.Caption
+	for _, item := range resp.Media.Items {
+		result.Items = append(result.Items, newGetItem(item))
+	}
+	if opts.listFormats {
+		return result
+	}
+
+	formats, err := core.DownloadMediaFormats(extractorCtx, resp.Media)
+	if err != nil {
+		result.Error = errorMessage(err)
+		return result
+	}
+	for _, format := range formats {
+		path := filepath.Join(opts.output, filepath.Base(format.FilePath))
+		if err := os.Rename(format.FilePath, path); err != nil {
+			result.Error = errorMessage(err)
+			return result
+		}
+		result.Files = append(result.Files, path)
+	}
+
+	return result
+}
+
+func newGetItem(item *models.MediaItem) *getItem {
+	formats := make([]*getFormat, 0, len(item.Formats))
+	for _, format := range item.Formats {
+		formats = append(formats, &getFormat{
+			FormatID:   format.FormatID,
+			Type:       format.Type,
+			VideoCodec: format.VideoCodec,
+			AudioCodec: format.AudioCodec,
+			FileSize:   format.FileSize,
+			Duration:   format.Duration,
+			Width:      format.Width,
+			Height:     format.Height,
+			Bitrate:    format.Bitrate,
+			Title:      format.Title,
+			Artist:     format.Artist,
+			URL:        format.URL,
+		})
+	}
+	return &getItem{Formats: formats}
+}
+
+func printResult(result *getResult, opts *getOptions, indent string) {
+	if result.Error != "" {
+		fmt.Fprintf(os.Stderr, "%s%s: %s\n", indent, result.URL, result.Error)
+		return
+	}
+	if len(result.Entries) > 0 {
+		fmt.Printf("%s%s: %d entries\n", indent, result.URL, len(result.Entries))
+		for _, entry := range result.Entries {
+			printResult(entry, opts, indent+"  ")
+		}
+		return
+	}
+	if opts.listFormats {
+		fmt.Printf("%s%s:\n", indent, result.URL)
+		for i, item := range result.media.Items {
+			fmt.Printf("%s  item %d:\n", indent, i)
+			for _, format := range item.Formats {
+				fmt.Printf("%s    %s\n", indent, format.ToString())
+			}
+		}
+		return
+	}
+	for _, file := range result.Files {
+		fmt.Println(indent + file)
+	}
+}
+
+func hasError(result *getResult) bool {
+	if result.Error != "" {
+		return true
+	}
+	for _, entry := range result.Entries {
+		if hasError(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// describes known errors in the default
+// language, returning the others as they are
+func errorMessage(err error) string {
+	var botError *util.Error
+	if !errors.As(err, &botError) {
+		return err.Error()
+	}
+	localizer := localization.New(config.Env.DefaultLanguage)
+	return localizer.T(&i18n.LocalizeConfig{
+		MessageID: botError.ID,
+	})
+}
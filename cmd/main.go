@@ -1,25 +1,42 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	_ "net/http/pprof" // profiler
 
+	"github.com/govdbot/govd/internal/api"
 	"github.com/govdbot/govd/internal/bot"
 	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/core"
 	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/localization"
 	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/plugins"
 	"github.com/govdbot/govd/internal/util"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "get" {
+		os.Exit(runGet(os.Args[2:]))
+	}
+
 	logger.Init()
 	defer logger.L.Sync()
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
 	config.Load()
 	logger.SetLevel(config.Env.LogLevel)
+	plugins.Load()
 
 	if !util.CheckFFmpeg() {
 		logger.L.Fatal("ffmpeg binary not found in PATH")
@@ -59,7 +76,40 @@ func main() {
 	database.Init()
 	util.CleanupDownloadsJob()
 
+	if config.Env.APIPort > 0 {
+		go api.Start()
+	}
+
 	go bot.Start()
 
-	select {}
+	<-ctx.Done()
+	stop()
+	shutdown()
+}
+
+// stops taking new work, lets the running tasks
+// finish within the grace period and cancels the rest
+func shutdown() {
+	logger.L.Infof("shutting down, waiting up to %s for running tasks", config.Env.ShutdownTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Env.ShutdownTimeout)
+	defer cancel()
+
+	bot.Stop()
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		api.Stop(ctx)
+	})
+	core.Shutdown(ctx)
+	wg.Wait()
+
+	// the jobs and handlers return soon after
+	// their tasks, then the database is unused
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer waitCancel()
+	bot.Wait(waitCtx)
+
+	database.Close()
+	logger.L.Info("shutdown complete")
 }
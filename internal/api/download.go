@@ -0,0 +1,84 @@
+package api
+
+import (
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/govdbot/govd/internal/core"
+)
+
+// downloads a format of a previous extraction, running it
+// through the same pipeline as the bot, and streams the file.
+// the format is selected with the item and format query
+// parameters, audio=true extracts only the audio.
+func handleDownload(w http.ResponseWriter, r *http.Request) {
+	extraction := getExtraction(r.PathValue("id"))
+	if extraction == nil {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{
+			Error: "extraction not found or expired",
+		})
+		return
+	}
+
+	query := r.URL.Query()
+	index := 0
+	if value := query.Get("item"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed >= len(extraction.media.Items) {
+			writeJSON(w, http.StatusBadRequest, &ErrorResponse{
+				Error: "invalid item index",
+			})
+			return
+		}
+		index = parsed
+	}
+	item := extraction.media.Items[index]
+
+	format := item.GetDefaultFormat()
+	if formatID := query.Get("format"); formatID != "" {
+		format = item.GetFormatByID(formatID)
+	}
+	if format == nil {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{
+			Error: "format not found",
+		})
+		return
+	}
+	// the pipeline updates the format metadata,
+	// keep the stored one untouched
+	formatCopy := *format
+	format = &formatCopy
+
+	extractorCtx := extraction.newContext(r.Context())
+	defer extractorCtx.FilesTracker.Cleanup()
+	extractorCtx.AudioOnly = query.Get("audio") == "true"
+
+	downloaded, err := core.DownloadItemFormat(extractorCtx, item, format)
+	if err != nil {
+		writeError(w, extractorCtx, err)
+		return
+	}
+
+	file, err := os.Open(downloaded.FilePath)
+	if err != nil {
+		writeError(w, extractorCtx, err)
+		return
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		writeError(w, extractorCtx, err)
+		return
+	}
+
+	fileName := filepath.Base(downloaded.FilePath)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(
+		"attachment",
+		map[string]string{"filename": fileName},
+	))
+	http.ServeContent(w, r, fileName, stat.ModTime(), file)
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// writes the error as a json response. known errors are
+// described to the client, unexpected ones are logged
+// and only their id is returned.
+func writeError(
+	w http.ResponseWriter,
+	extractorCtx *models.ExtractorContext,
+	err error,
+) {
+	if errors.Is(err, context.Canceled) {
+		// the client went away
+		return
+	}
+	if errors.Is(err, core.ErrNoMedia) {
+		writeJSON(w, http.StatusNotFound, &ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+	if message := errorMessage(err); message != "" {
+		writeJSON(w, http.StatusUnprocessableEntity, &ErrorResponse{
+			Error: message,
+		})
+		return
+	}
+
+	errorID := util.HashedError(err)
+	extractorCtx.Errorf("unexpected error: [%s] %v", errorID, err)
+	database.Q().LogError(
+		context.Background(),
+		database.LogErrorParams{
+			ID:      errorID,
+			Message: err.Error(),
+		},
+	)
+	writeJSON(w, http.StatusInternalServerError, &ErrorResponse{
+		Error:   "internal error",
+		ErrorID: errorID,
+	})
+}
+
+// returns the description of a known error
+// in the default language, empty otherwise
+func errorMessage(err error) string {
+	var botError *util.Error
+	if !errors.As(err, &botError) {
+		return ""
+	}
+	localizer := localization.New(config.Env.DefaultLanguage)
+	return localizer.T(&i18n.LocalizeConfig{
+		MessageID: botError.ID,
+	})
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/extractors"
+	"github.com/govdbot/govd/internal/models"
+)
+
+// extracts the media of the url, returning
+// its items and formats without downloading them
+func handleExtract(w http.ResponseWriter, r *http.Request) {
+	var req ExtractRequest
+	err := sonic.ConfigDefault.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req.URL == "" {
+		writeJSON(w, http.StatusBadRequest, &ErrorResponse{
+			Error: "invalid request body",
+		})
+		return
+	}
+
+	extractorCtx := extractors.FromURL(req.URL)
+	if extractorCtx == nil || extractorCtx.Extractor == nil {
+		writeJSON(w, http.StatusUnprocessableEntity, &ErrorResponse{
+			Error: "unsupported url",
+		})
+		return
+	}
+
+	resp, err := extractorCtx.Extractor.GetFunc(extractorCtx)
+	if err != nil {
+		extractorCtx.CancelFunc()
+		writeError(w, extractorCtx, err)
+		return
+	}
+
+	res := &ExtractResponse{
+		ExtractorID: extractorCtx.Extractor.ID,
+		ContentID:   extractorCtx.ContentID,
+		ContentURL:  extractorCtx.ContentURL,
+	}
+
+	if len(resp.Entries) > 0 {
+		extractorCtx.CancelFunc()
+		res.Entries = resp.Entries
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+	if resp.Media == nil || len(resp.Media.Items) == 0 {
+		extractorCtx.CancelFunc()
+		writeError(w, extractorCtx, core.ErrNoMedia)
+		return
+	}
+
+	// the context is canceled once the extraction expires
+	res.ID = storeExtraction(extractorCtx, resp.Media)
+	res.Caption = resp.Media.Caption
+	res.NSFW = resp.Media.NSFW
+	for i, item := range resp.Media.Items {
+		res.Items = append(res.Items, newItemResponse(
+			extractorCtx, res.ID, i, item,
+		))
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func newItemResponse(
+	extractorCtx *models.ExtractorContext,
+	id string,
+	index int,
+	item *models.MediaItem,
+) *ItemResponse {
+	res := &ItemResponse{
+		Formats: make([]*FormatResponse, 0, len(item.Formats)),
+	}
+	for _, format := range item.Formats {
+		formatRes := &FormatResponse{
+			FormatID:   format.FormatID,
+			Type:       format.Type,
+			VideoCodec: format.VideoCodec,
+			AudioCodec: format.AudioCodec,
+			FileSize:   format.FileSize,
+			Duration:   format.Duration,
+			Width:      format.Width,
+			Height:     format.Height,
+			Bitrate:    format.Bitrate,
+			Title:      format.Title,
+			Artist:     format.Artist,
+		}
+		if err := core.ValidateFormat(extractorCtx, format); err != nil {
+			formatRes.Unavailable = errorMessage(err)
+		} else {
+			query := url.Values{}
+			query.Set("item", strconv.Itoa(index))
+			query.Set("format", format.FormatID)
+			formatRes.DownloadURL = "/download/" + id + "?" + query.Encode()
+		}
+		res.Formats = append(res.Formats, formatRes)
+	}
+	return res
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/logger"
+)
+
+// set once the rest api started
+var server atomic.Pointer[http.Server]
+
+// starts the rest api, exposing the extractors
+// to services other than the telegram bot
+func Start() {
+	if len(config.Env.APIKeys) == 0 {
+		logger.L.Warn("API_KEYS is not set, the rest api is disabled")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /extract", handleExtract)
+	mux.HandleFunc("GET /download/{id}", handleDownload)
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf("0.0.0.0:%d", config.Env.APIPort),
+		Handler:           authenticate(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	server.Store(srv)
+
+	logger.L.Infof("starting rest api on port %d", config.Env.APIPort)
+	err := srv.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.L.Fatalf("failed to start rest api: %v", err)
+	}
+}
+
+// stops accepting requests and waits for the
+// running ones, until the context is done
+func Stop(ctx context.Context) {
+	srv := server.Load()
+	if srv == nil {
+		return
+	}
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.L.Warnf("failed to stop rest api: %v", err)
+	}
+}
+
+// accepts the key either in the X-API-Key
+// header or as a bearer token
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		if key == "" {
+			key, _ = strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		}
+		if !isValidKey(key) {
+			writeJSON(w, http.StatusUnauthorized, &ErrorResponse{
+				Error: "invalid api key",
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isValidKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, validKey := range config.Env.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(validKey)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := sonic.ConfigDefault.NewEncoder(w).Encode(v); err != nil {
+		logger.L.Debugf("failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import "github.com/govdbot/govd/internal/database"
+
+type ExtractRequest struct {
+	URL string `json:"url"`
+}
+
+type ExtractResponse struct {
+	// identifies the extraction in download requests,
+	// empty for collections
+	ID          string `json:"id,omitempty"`
+	ExtractorID string `json:"extractor_id"`
+	ContentID   string `json:"content_id"`
+	ContentURL  string `json:"content_url"`
+	Caption     string `json:"caption,omitempty"`
+	NSFW        bool   `json:"nsfw"`
+
+	Items []*ItemResponse `json:"items,omitempty"`
+
+	// urls of the entries, for collections
+	Entries []string `json:"entries,omitempty"`
+}
+
+type ItemResponse struct {
+	Formats []*FormatResponse `json:"formats"`
+}
+
+type FormatResponse struct {
+	FormatID   string              `json:"format_id"`
+	Type       database.MediaType  `json:"type"`
+	VideoCodec database.MediaCodec `json:"video_codec,omitempty"`
+	AudioCodec database.MediaCodec `json:"audio_codec,omitempty"`
+	FileSize   int64               `json:"file_size,omitempty"`
+	Duration   int32               `json:"duration,omitempty"`
+	Width      int32               `json:"width,omitempty"`
+	Height     int32               `json:"height,omitempty"`
+	Bitrate    int64               `json:"bitrate,omitempty"`
+	Title      string              `json:"title,omitempty"`
+	Artist     string              `json:"artist,omitempty"`
+
+	// relative url downloading the format, empty
+	// when the format exceeds the configured limits
+	DownloadURL string `json:"download_url,omitempty"`
+	// why the format can't be downloaded, if so
+	Unavailable string `json:"unavailable,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	ErrorID string `json:"error_id,omitempty"`
+}
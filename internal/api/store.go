@@ -0,0 +1,61 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	// format urls usually expire shortly, so
+	// extractions are not kept for long
+	extractionTTL = 10 * time.Minute
+	// bounds the memory used by the extractions
+	// of clients that never download them
+	maxExtractions = 1000
+)
+
+type extraction struct {
+	extractorCtx *models.ExtractorContext
+	media        *models.Media
+}
+
+var extractions = expirable.NewLRU(
+	maxExtractions,
+	func(_ string, e *extraction) {
+		e.extractorCtx.CancelFunc()
+	},
+	extractionTTL,
+)
+
+// keeps the extraction for the following download requests
+func storeExtraction(
+	extractorCtx *models.ExtractorContext,
+	media *models.Media,
+) string {
+	id := uuid.NewString()
+	extractions.Add(id, &extraction{
+		extractorCtx: extractorCtx,
+		media:        media,
+	})
+	return id
+}
+
+func getExtraction(id string) *extraction {
+	e, _ := extractions.Get(id)
+	return e
+}
+
+// returns a context for downloading from the extraction,
+// bound to the given request context. the context is copied
+// so concurrent downloads don't share their state.
+func (e *extraction) newContext(ctx context.Context) *models.ExtractorContext {
+	extractorCtx := *e.extractorCtx
+	extractorCtx.Context = ctx
+	extractorCtx.FilesTracker = models.NewFilesTracker()
+	extractorCtx.ProgressFunc = nil
+	return &extractorCtx
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/util"
+)
+
+// downloads only the audio of the given links.
+// the links can also be in the replied message.
+func AudioHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
+	message := ctx.EffectiveMessage
+
+	urls := util.URLsFromMessage(message)
+	if len(urls) == 0 && message.ReplyToMessage != nil {
+		urls = util.URLsFromMessage(message.ReplyToMessage)
+	}
+	if len(urls) == 0 {
+		return ext.EndGroups
+	}
+
+	return handleURLs(bot, ctx, urls, true)
+}
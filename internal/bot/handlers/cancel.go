@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/jobs"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// cancels the queued and active downloads of the user.
+// in groups, admins cancel every download of the chat.
+func CancelHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
+	chat, err := util.ChatFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	localizer := localization.New(chat.Language)
+
+	chatID := ctx.EffectiveChat.Id
+	userID := ctx.EffectiveUser.Id
+	if ctx.EffectiveChat.Type != gotgbot.ChatTypePrivate &&
+		util.IsUserAdmin(bot, ctx.EffectiveUser, chatID) {
+		userID = 0
+	}
+
+	pending, err := jobs.CancelPending(bot, chatID, userID)
+	if err != nil {
+		return err
+	}
+	// running tasks confirm on their own
+	running := core.CancelChatTasks(chatID, userID)
+
+	var messageID string
+	switch {
+	case pending > 0:
+		messageID = localization.DownloadCanceledMessage.ID
+	case running == 0:
+		messageID = localization.NoActiveDownloadsMessage.ID
+	default:
+		return ext.EndGroups
+	}
+	ctx.EffectiveMessage.Reply(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: messageID,
+		}),
+		nil,
+	)
+	return ext.EndGroups
+}
+
+func CancelButtonHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
+	// cancel.taskID
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 2 {
+		return nil
+	}
+	taskID := parts[1]
+
+	chat, err := util.ChatFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	localizer := localization.New(chat.Language)
+
+	userID, ok := core.GetTaskUser(taskID)
+	if !ok {
+		// the task already completed
+		ctx.CallbackQuery.Answer(bot, nil)
+		return nil
+	}
+	// only the user who sent the link
+	// or an admin can cancel the download
+	if ctx.EffectiveUser.Id != userID &&
+		!util.CheckAdminPermission(bot, ctx, localizer) {
+		return nil
+	}
+
+	core.CancelTask(taskID)
+	ctx.CallbackQuery.Answer(bot, nil)
+	return ext.EndGroups
+}
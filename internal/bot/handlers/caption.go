@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// sets an empty template, e.g. to remove the header
+const emptyCaptionTemplate = "-"
+
+// shows or changes the caption templates of the chat:
+// /caption header <template>, /caption description <template>
+// or /caption reset. in groups, only admins can use it.
+func CaptionHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
+	chat, err := util.ChatFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	localizer := localization.New(chat.Language)
+
+	isGroup := chat.Type == database.ChatTypeGroup
+	if isGroup && !util.CheckAdminPermission(bot, ctx, localizer) {
+		return ext.EndGroups
+	}
+
+	text := strings.TrimSpace(ctx.EffectiveMessage.Text)
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		replyCaptionTemplates(bot, ctx, localizer, chat)
+		return ext.EndGroups
+	}
+
+	action := fields[1]
+	start := len(fields[0])
+	start += strings.Index(text[start:], action) + len(action)
+	template := strings.TrimSpace(text[start:])
+	if template == "" && action != "reset" {
+		replyCaptionTemplates(bot, ctx, localizer, chat)
+		return ext.EndGroups
+	}
+	if template == emptyCaptionTemplate {
+		template = ""
+	}
+
+	params := database.SetChatCaptionTemplateParams{
+		ChatID:             chat.ChatID,
+		CaptionHeader:      chat.CaptionHeader,
+		CaptionDescription: chat.CaptionDescription,
+	}
+	switch action {
+	case "header":
+		params.CaptionHeader = pgtype.Text{String: template, Valid: true}
+	case "description":
+		params.CaptionDescription = pgtype.Text{String: template, Valid: true}
+	case "reset":
+		params.CaptionHeader = pgtype.Text{}
+		params.CaptionDescription = pgtype.Text{}
+	default:
+		replyCaptionTemplates(bot, ctx, localizer, chat)
+		return ext.EndGroups
+	}
+
+	if action != "reset" {
+		if err := core.ValidateCaptionTemplate(template); err != nil {
+			replyInvalidTemplate(bot, ctx, localizer, err)
+			return ext.EndGroups
+		}
+	}
+
+	chat.CaptionHeader = params.CaptionHeader
+	chat.CaptionDescription = params.CaptionDescription
+	header, description := core.CaptionTemplates(chat)
+	preview := core.PreviewCaption(bot.Username, header, description)
+
+	// telegram has the last word on the html of the
+	// template, so the preview is sent before saving it
+	msg, err := ctx.EffectiveMessage.Reply(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.CaptionTemplatePreviewMessage.ID,
+		})+"\n\n"+preview,
+		&gotgbot.SendMessageOpts{
+			LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+				IsDisabled: true,
+			},
+		},
+	)
+	if err != nil {
+		replyInvalidTemplate(bot, ctx, localizer, err)
+		return ext.EndGroups
+	}
+
+	err = database.Q().SetChatCaptionTemplate(context.Background(), params)
+	if err != nil {
+		msg.Reply(
+			bot, "⚠️ "+localizer.T(&i18n.LocalizeConfig{
+				MessageID: localization.ErrorMessage.ID,
+			}),
+			nil,
+		)
+		return err
+	}
+
+	msg.EditText(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.CaptionTemplateSavedMessage.ID,
+		})+"\n\n"+preview,
+		&gotgbot.EditMessageTextOpts{
+			LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+				IsDisabled: true,
+			},
+		},
+	)
+	return ext.EndGroups
+}
+
+// replies with the current templates of the chat and the usage
+func replyCaptionTemplates(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	localizer *localization.Localizer,
+	chat *database.GetOrCreateChatRow,
+) {
+	header, description := core.CaptionTemplates(chat)
+	placeholders := core.CaptionPlaceholders()
+	for i, name := range placeholders {
+		placeholders[i] = "<code>{{" + name + "}}</code>"
+	}
+	ctx.EffectiveMessage.Reply(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.CaptionTemplateMessage.ID,
+			TemplateData: map[string]string{
+				"Header":       html.EscapeString(header),
+				"Description":  html.EscapeString(description),
+				"Placeholders": strings.Join(placeholders, ", "),
+				"SectionStart": "{{#name}}",
+				"SectionEnd":   "{{/name}}",
+			},
+		}),
+		nil,
+	)
+}
+
+func replyInvalidTemplate(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	localizer *localization.Localizer,
+	err error,
+) {
+	ctx.EffectiveMessage.Reply(
+		bot, "⚠️ "+localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.ErrorInvalidCaptionTemplate.ID,
+			TemplateData: map[string]string{
+				"Reason": html.EscapeString(err.Error()),
+			},
+		}),
+		nil,
+	)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/jobs"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// confirms or cancels the download of
+// a collection with many entries
+func CollectionHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
+	// collection.action.jobID
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 3 {
+		return nil
+	}
+	action := parts[1]
+	jobID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil
+	}
+
+	chat, err := util.ChatFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	localizer := localization.New(chat.Language)
+
+	userID, ok := jobs.GetCollectionUser(jobID)
+	if !ok {
+		// already confirmed or canceled
+		ctx.CallbackQuery.Answer(bot, nil)
+		ctx.EffectiveMessage.Delete(bot, nil)
+		return ext.EndGroups
+	}
+	// only the user who sent the link
+	// or an admin can answer the prompt
+	if ctx.EffectiveUser.Id != userID &&
+		!util.CheckAdminPermission(bot, ctx, localizer) {
+		return nil
+	}
+
+	var text string
+	switch action {
+	case "confirm":
+		count, err := jobs.ConfirmCollection(jobID)
+		if errors.Is(err, jobs.ErrJobNotWaiting) {
+			ctx.CallbackQuery.Answer(bot, nil)
+			return ext.EndGroups
+		}
+		if err != nil {
+			return err
+		}
+		text = localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.CollectionQueuedMessage.ID,
+			TemplateData: map[string]int{
+				"Count": count,
+			},
+		})
+	case "cancel":
+		err := jobs.DiscardCollection(jobID)
+		if errors.Is(err, jobs.ErrJobNotWaiting) {
+			ctx.CallbackQuery.Answer(bot, nil)
+			return ext.EndGroups
+		}
+		if err != nil {
+			return err
+		}
+		text = localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.DownloadCanceledMessage.ID,
+		})
+	default:
+		return nil
+	}
+
+	ctx.CallbackQuery.Answer(bot, nil)
+	ctx.EffectiveMessage.EditText(bot, text, nil)
+	return ext.EndGroups
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+func FormatPickerHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
+	// picker.taskID.index
+	parts := strings.Split(ctx.CallbackQuery.Data, ".")
+	if len(parts) < 3 {
+		return nil
+	}
+	taskID := parts[1]
+	index, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil
+	}
+
+	chat, err := util.ChatFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	localizer := localization.New(chat.Language)
+
+	task, ok := core.GetPickerTask(taskID)
+	if !ok {
+		ctx.CallbackQuery.Answer(bot, &gotgbot.AnswerCallbackQueryOpts{
+			Text: localizer.T(&i18n.LocalizeConfig{
+				MessageID: localization.FormatPickerExpiredMessage.ID,
+			}),
+			ShowAlert: true,
+		})
+		ctx.EffectiveMessage.Delete(bot, nil)
+		return nil
+	}
+	// only the user who sent the link
+	// or an admin can pick the format
+	if ctx.EffectiveUser.Id != task.UserID &&
+		!util.CheckAdminPermission(bot, ctx, localizer) {
+		return nil
+	}
+
+	item := task.Media.Items[0]
+	if index < 0 || index >= len(item.Formats) {
+		return nil
+	}
+
+	// removing the task ensures that
+	// the format is picked only once
+	if !core.RemovePickerTask(taskID) {
+		return nil
+	}
+
+	// the original context may have expired
+	// while waiting for the user to pick a format
+	extractorCtx := task.ExtractorCtx
+	extractorCtx.Context, extractorCtx.CancelFunc = context.WithTimeout(
+		context.Background(),
+		5*time.Minute,
+	)
+	defer extractorCtx.CancelFunc()
+
+	ctx.CallbackQuery.Answer(bot, nil)
+	ctx.EffectiveMessage.EditText(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.FormatPickerDownloadingMessage.ID,
+		}),
+		nil,
+	)
+
+	err = core.HandlePickerResultTask(
+		bot, ctx, task,
+		item.Formats[index].FormatID,
+	)
+	if err != nil {
+		core.HandleError(bot, ctx, extractorCtx, err)
+	}
+	return ext.EndGroups
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/extractors"
+	"github.com/govdbot/govd/internal/util"
+)
+
+// removes the cached media and failures of the url,
+// so that the next request extracts it again
+func PurgeHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
+	ok := util.IsBotAdmin(ctx)
+	if !ok {
+		return ext.EndGroups
+	}
+
+	args := ctx.Args()
+	if len(args) < 2 {
+		ctx.EffectiveMessage.Reply(bot, "usage: /purge &lt;url&gt;", nil)
+		return ext.EndGroups
+	}
+
+	extractorCtx := extractors.FromURL(args[1])
+	if extractorCtx == nil || extractorCtx.Extractor == nil {
+		ctx.EffectiveMessage.Reply(bot, "unsupported url", nil)
+		return ext.EndGroups
+	}
+	defer extractorCtx.CancelFunc()
+
+	key := util.EscapeHTML(extractorCtx.Key())
+	purged, err := core.PurgeMedia(context.Background(), extractorCtx)
+	if err != nil {
+		return err
+	}
+
+	text := fmt.Sprintf("nothing cached for <code>%s</code>", key)
+	if purged {
+		text = fmt.Sprintf("purged <code>%s</code>", key)
+	}
+	ctx.EffectiveMessage.Reply(bot, text, nil)
+	return ext.EndGroups
+}
@@ -108,6 +108,51 @@ var botSettings = []BotSettings{
 		},
 		OptionsChunk: 5,
 	},
+	{
+		ID:             "video_quality",
+		ButtonKey:      localization.VideoQualityButton.ID,
+		DescriptionKey: localization.VideoQualitySettingsMessage.ID,
+
+		Type:  SettingsTypeSelect,
+		Scope: SettingsScopeAll,
+
+		OptionsFunc: func(l *localization.Localizer) []*BotSettingsOptions {
+			qualities := []int32{360, 480, 720, 1080}
+			options := make([]*BotSettingsOptions, 0, len(qualities)+1)
+
+			for _, quality := range qualities {
+				options = append(options, &BotSettingsOptions{
+					Name:  strconv.Itoa(int(quality)) + "p",
+					Value: quality,
+				})
+			}
+			options = append(options, &BotSettingsOptions{
+				Name: l.T(&i18n.LocalizeConfig{
+					MessageID: localization.VideoQualityBestButton.ID,
+				}),
+				Value: int32(0),
+			})
+			return options
+		},
+		SetValueFunc: func(ctx context.Context, chatID int64, value any) error {
+			quality, ok := value.(int32)
+			if !ok {
+				if f, ok := value.(float64); ok {
+					quality = int32(f)
+				} else {
+					return nil
+				}
+			}
+			return database.Q().SetChatVideoQuality(ctx, database.SetChatVideoQualityParams{
+				VideoQuality: quality,
+				ChatID:       chatID,
+			})
+		},
+		GetCurrentValueFunc: func(res *database.GetOrCreateChatRow) any {
+			return res.VideoQuality
+		},
+		OptionsChunk: 5,
+	},
 	{
 		ID:             "silent_mode",
 		ButtonKey:      localization.SilentModeButton.ID,
@@ -153,6 +198,21 @@ var botSettings = []BotSettings{
 			return res.DeleteLinks
 		},
 	},
+	{
+		ID:             "format_picker",
+		ButtonKey:      localization.FormatPickerButton.ID,
+		DescriptionKey: localization.FormatPickerSettingsMessage.ID,
+
+		Type:  SettingsTypeToggle,
+		Scope: SettingsScopeAll,
+
+		ToggleFunc: func(ctx context.Context, chatID int64) error {
+			return database.Q().ToggleChatFormatPicker(ctx, chatID)
+		},
+		GetCurrentValueFunc: func(res *database.GetOrCreateChatRow) any {
+			return res.FormatPicker
+		},
+	},
 	{
 		ID:             "disabled_extractors",
 		ButtonKey:      localization.ExtractorsButton.ID,
@@ -6,23 +6,25 @@ import (
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext/handlers/filters/message"
+	"github.com/govdbot/govd/internal/config"
 	"github.com/govdbot/govd/internal/core"
 	"github.com/govdbot/govd/internal/extractors"
+	"github.com/govdbot/govd/internal/jobs"
 	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/models"
 	"github.com/govdbot/govd/internal/util"
 )
 
 func URLFilter(msg *gotgbot.Message) bool {
-	return message.Text(msg) &&
-		!message.Command(msg) &&
-		message.Entity("url")(msg)
+	return !message.Command(msg) &&
+		len(util.URLsFromMessage(msg)) > 0
 }
 
 func URLHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
 	message := ctx.EffectiveMessage
 
-	url := util.URLFromMessage(message)
-	if url == "" {
+	urls := util.URLsFromMessage(message)
+	if len(urls) == 0 {
 		return ext.EndGroups
 	}
 
@@ -30,32 +32,77 @@ func URLHandler(bot *gotgbot.Bot, ctx *ext.Context) error {
 		return ext.EndGroups
 	}
 
-	extractorCtx := extractors.FromURL(url)
-	if extractorCtx == nil || extractorCtx.Extractor == nil {
+	audioOnly := util.HasHashtagEntity(message, "audio")
+
+	return handleURLs(bot, ctx, urls, audioOnly)
+}
+
+func handleURLs(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	urls []string,
+	audioOnly bool,
+) error {
+	// the downloads run in a job, these contexts
+	// are only used to validate the urls
+	var extractorCtxs []*models.ExtractorContext
+	defer func() {
+		for _, extractorCtx := range extractorCtxs {
+			extractorCtx.CancelFunc()
+		}
+	}()
+
+	for _, url := range urls {
+		extractorCtx := extractors.FromURL(url)
+		if extractorCtx == nil || extractorCtx.Extractor == nil {
+			continue
+		}
+		extractorCtxs = append(extractorCtxs, extractorCtx)
+	}
+	if len(extractorCtxs) == 0 {
 		return ext.EndGroups
 	}
 
-	defer extractorCtx.CancelFunc()
-
 	chat, err := util.ChatFromContext(ctx)
 	if err != nil {
 		logger.L.Errorf("failed to get settings from context: %v", err)
 		return ext.EndGroups
 	}
-	if chat != nil && slices.Contains(chat.DisabledExtractors, extractorCtx.Extractor.ID) {
+
+	// redirects are already resolved, so the
+	// job can start from the matched urls
+	maxLinks := max(config.Env.MaxLinksPerMessage, 1)
+	var contentURLs []string
+	for _, extractorCtx := range extractorCtxs {
+		extractorCtx.SetChat(chat)
+		if slices.Contains(chat.DisabledExtractors, extractorCtx.Extractor.ID) {
+			continue
+		}
+		if slices.Contains(contentURLs, extractorCtx.ContentURL) {
+			continue
+		}
+		if len(contentURLs) >= maxLinks {
+			logger.L.Debugf("ignoring links over the limit of %d", maxLinks)
+			break
+		}
+		contentURLs = append(contentURLs, extractorCtx.ContentURL)
+	}
+	if len(contentURLs) == 0 {
 		return ext.EndGroups
 	}
-	extractorCtx.SetChat(chat)
 
 	err = util.SendTypingAction(bot, chat.ChatID)
 	if err != nil {
-		core.HandleError(bot, ctx, extractorCtx, err)
+		core.HandleError(bot, ctx, extractorCtxs[0], err)
 		return ext.EndGroups
 	}
 
-	err = core.HandleDownloadTask(bot, ctx, extractorCtx)
+	err = jobs.Enqueue(
+		bot, ctx.EffectiveMessage,
+		contentURLs, audioOnly,
+	)
 	if err != nil {
-		core.HandleError(bot, ctx, extractorCtx, err)
+		core.HandleError(bot, ctx, extractorCtxs[0], err)
 		return ext.EndGroups
 	}
 
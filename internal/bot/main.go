@@ -1,11 +1,14 @@
 package bot
 
 import (
+	"context"
 	"log/slog"
 	"runtime/debug"
+	"sync/atomic"
 	"time"
 
 	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/jobs"
 	"github.com/govdbot/govd/internal/logger"
 	"go.uber.org/zap/exp/zapslog"
 
@@ -28,20 +31,71 @@ var allowedUpdates = []string{
 	"my_chat_member",
 }
 
+// set once the bot receives updates
+var updater atomic.Pointer[ext.Updater]
+
 func Start() {
 	bot := createBot()
 	dispatcher := newDispatcher()
 
+	jobs.Start(bot)
+
 	// prometheus monitoring
 	go monitorDispatcherBuffer(dispatcher)
 
-	updater := ext.NewUpdater(dispatcher, &ext.UpdaterOpts{
+	u := ext.NewUpdater(dispatcher, &ext.UpdaterOpts{
 		Logger: slog.New(zapslog.NewHandler(logger.L.Desugar().Core())),
 	})
 
+	if config.Env.WebhookURL != "" {
+		err := startWebhook(bot, u)
+		if err != nil {
+			logger.L.Fatalf("failed to start webhook: %v", err)
+		}
+	} else {
+		err := startPolling(bot, u)
+		if err != nil {
+			logger.L.Fatalf("failed to start polling: %v", err)
+		}
+	}
+
+	updater.Store(u)
+
+	logger.L.Infof("bot started with username: %s", bot.Username)
+}
+
+// stops receiving updates and claiming jobs,
+// the ones being handled are left running
+func Stop() {
+	jobs.Stop()
+	if u := updater.Load(); u != nil {
+		u.StopAllBots()
+	}
+}
+
+// waits for the updates and jobs being
+// handled, until the context is done
+func Wait(ctx context.Context) {
+	jobs.Wait(ctx)
+	u := updater.Load()
+	if u == nil {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		u.Dispatcher.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+func startPolling(bot *gotgbot.Bot, u *ext.Updater) error {
 	logger.L.Debugf("starting updates polling. allowed updates: %v", allowedUpdates)
-	err := updater.StartPolling(bot, &ext.PollingOpts{
-		DropPendingUpdates: true,
+	return u.StartPolling(bot, &ext.PollingOpts{
+		DropPendingUpdates: !config.Env.KeepPendingUpdates,
 		GetUpdatesOpts: &gotgbot.GetUpdatesOpts{
 			Timeout: 9,
 			RequestOpts: &gotgbot.RequestOpts{
@@ -50,11 +104,6 @@ func Start() {
 			AllowedUpdates: allowedUpdates,
 		},
 	})
-	if err != nil {
-		logger.L.Fatalf("failed to start polling: %v", err)
-	}
-
-	logger.L.Infof("bot started with username: %s", bot.Username)
 }
 
 func createBot() *gotgbot.Bot {
@@ -117,6 +166,34 @@ func registerHandlers(dispatcher *ext.Dispatcher) *ext.Dispatcher {
 		botHandlers.InlineLoadingHandler,
 	))
 
+	// audio
+	dispatcher.AddHandler(handlers.NewCommand(
+		"audio",
+		botHandlers.AudioHandler,
+	))
+
+	// format picker
+	dispatcher.AddHandler(handlers.NewCallback(
+		callbackquery.Prefix("picker."),
+		botHandlers.FormatPickerHandler,
+	))
+
+	// cancel
+	dispatcher.AddHandler(handlers.NewCommand(
+		"cancel",
+		botHandlers.CancelHandler,
+	))
+	dispatcher.AddHandler(handlers.NewCallback(
+		callbackquery.Prefix("cancel."),
+		botHandlers.CancelButtonHandler,
+	))
+
+	// collections
+	dispatcher.AddHandler(handlers.NewCallback(
+		callbackquery.Prefix("collection."),
+		botHandlers.CollectionHandler,
+	))
+
 	// start
 	dispatcher.AddHandler(handlers.NewCommand(
 		"start",
@@ -165,6 +242,12 @@ func registerHandlers(dispatcher *ext.Dispatcher) *ext.Dispatcher {
 		botSettings.SettingsManyHandler,
 	))
 
+	// caption templates
+	dispatcher.AddHandler(handlers.NewCommand(
+		"caption",
+		botHandlers.CaptionHandler,
+	))
+
 	// other
 	dispatcher.AddHandler(handlers.NewCallback(
 		callbackquery.Equal("close"),
@@ -174,10 +257,14 @@ func registerHandlers(dispatcher *ext.Dispatcher) *ext.Dispatcher {
 		"derr",
 		botHandlers.DecodeErrorHandler,
 	))
-	dispatcher.AddHandlerToGroup(handlers.NewMessage(
-		message.All,
-		botHandlers.OldMessagesHandler,
-	), -100)
+	// pending updates are kept to handle the messages
+	// sent while the bot was offline, however old
+	if !config.Env.KeepPendingUpdates {
+		dispatcher.AddHandlerToGroup(handlers.NewMessage(
+			message.All,
+			botHandlers.OldMessagesHandler,
+		), -100)
+	}
 	dispatcher.AddHandler(handlers.NewCommand(
 		"stats",
 		botHandlers.StatsHandler,
@@ -186,6 +273,10 @@ func registerHandlers(dispatcher *ext.Dispatcher) *ext.Dispatcher {
 		callbackquery.Prefix("stats"),
 		botHandlers.StatsCallbackHandler,
 	))
+	dispatcher.AddHandler(handlers.NewCommand(
+		"purge",
+		botHandlers.PurgeHandler,
+	))
 
 	// whitelist
 	if len(config.Env.Whitelist) > 0 {
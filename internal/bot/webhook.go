@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/logger"
+)
+
+// path the updates are served on, when
+// the webhook url doesn't have one
+const defaultWebhookPath = "webhook"
+
+// characters telegram allows in the secret token
+var secretTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// serves the updates sent by telegram to the webhook url.
+// the path of the url is served as it is, so the reverse
+// proxy in front of the bot must not rewrite it.
+func startWebhook(bot *gotgbot.Bot, u *ext.Updater) error {
+	webhookURL, err := url.Parse(config.Env.WebhookURL)
+	if err != nil || webhookURL.Scheme != "https" || webhookURL.Host == "" {
+		return fmt.Errorf("WEBHOOK_URL must be an https url: %s", config.Env.WebhookURL)
+	}
+	path := strings.Trim(webhookURL.Path, "/")
+	if path == "" {
+		path = defaultWebhookPath
+		webhookURL = webhookURL.JoinPath(path)
+	}
+
+	secret := config.Env.WebhookSecret
+	switch {
+	case secret == "":
+		logger.L.Warn("WEBHOOK_SECRET is not set, the origin of the updates is not verified")
+	case !secretTokenPattern.MatchString(secret):
+		return fmt.Errorf("WEBHOOK_SECRET must be 1-256 characters among A-Z, a-z, 0-9, _ and -")
+	}
+
+	logger.L.Debugf(
+		"starting webhook on %s/%s. allowed updates: %v",
+		config.Env.WebhookListenAddr, path, allowedUpdates,
+	)
+	err = u.StartWebhook(bot, path, ext.WebhookOpts{
+		ListenAddr:        config.Env.WebhookListenAddr,
+		ReadHeaderTimeout: 10 * time.Second,
+		CertFile:          config.Env.WebhookCertFile,
+		KeyFile:           config.Env.WebhookKeyFile,
+		SecretToken:       secret,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = bot.SetWebhook(webhookURL.String(), &gotgbot.SetWebhookOpts{
+		AllowedUpdates:     allowedUpdates,
+		DropPendingUpdates: !config.Env.KeepPendingUpdates,
+		SecretToken:        secret,
+		RequestOpts: &gotgbot.RequestOpts{
+			Timeout: time.Second * 10,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+	return nil
+}
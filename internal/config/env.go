@@ -1,26 +1,44 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/logger"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 )
 
 var Env = GetDefaultConfig()
 
-func loadFromEnv() {
+func loadFromEnv(botRequired bool) {
 	godotenv.Load()
 	parseEnvString("DB_HOST", &Env.DBHost, false)
 	parseEnvInt("DB_PORT", &Env.DBPort, false)
 	parseEnvString("DB_NAME", &Env.DBName, false)
 	parseEnvString("DB_USER", &Env.DBUser, false)
 	parseEnvString("DB_PASSWORD", &Env.DBPassword, false)
-	parseEnvString("BOT_TOKEN", &Env.BotToken, true)
+	parseEnvString("BOT_TOKEN", &Env.BotToken, botRequired)
 	parseEnvString("BOT_API_URL", &Env.BotAPIURL, false)
+	parseEnvBool("BOT_API_LOCAL", &Env.BotAPILocal, false)
+	parseEnvString("BOT_API_DOWNLOADS_DIR", &Env.BotAPIDownloadsDir, false)
 	parseEnvInt("CONCURRENT_UPDATES", &Env.ConcurrentUpdates, false)
+	parseEnvBool("KEEP_PENDING_UPDATES", &Env.KeepPendingUpdates, false)
+	parseEnvString("WEBHOOK_URL", &Env.WebhookURL, false)
+	parseEnvString("WEBHOOK_LISTEN_ADDR", &Env.WebhookListenAddr, false)
+	parseEnvString("WEBHOOK_SECRET", &Env.WebhookSecret, false)
+	parseEnvString("WEBHOOK_CERT_FILE", &Env.WebhookCertFile, false)
+	parseEnvString("WEBHOOK_KEY_FILE", &Env.WebhookKeyFile, false)
+	parseEnvInt("JOB_WORKERS", &Env.JobWorkers, false)
+	parseEnvInt("JOB_MAX_ATTEMPTS", &Env.JobMaxAttempts, false)
+	parseEnvInt("MAX_CHAT_JOBS", &Env.MaxChatJobs, false)
+	parseEnvInt("MAX_CONCURRENT_DOWNLOADS", &Env.MaxConcurrentDownloads, false)
+	parseEnvInt("MAX_LINKS_PER_MESSAGE", &Env.MaxLinksPerMessage, false)
+	parseEnvDuration("SHUTDOWN_TIMEOUT", &Env.ShutdownTimeout, false)
+	parseEnvInt("MAX_COLLECTION_ENTRIES", &Env.MaxCollectionEntries, false)
+	parseEnvInt("COLLECTION_CONFIRM_ENTRIES", &Env.CollectionConfirmEntries, false)
 	parseEnvString("DOWNLOADS_DIR", &Env.DownloadsDirectory, false)
 	parseEnvString("PROXY", &Env.Proxy, false)
 	parseEnvDuration("MAX_DURATION", &Env.MaxDuration, false)
@@ -28,19 +46,36 @@ func loadFromEnv() {
 	parseEnvString("REPO_URL", &Env.RepoURL, false)
 	parseEnvInt("PROFILER_PORT", &Env.ProfilerPort, false)
 	parseEnvInt("METRICS_PORT", &Env.MetricsPort, false)
+	parseEnvInt("API_PORT", &Env.APIPort, false)
+	parseEnvStringSlice("API_KEYS", &Env.APIKeys, false)
+	parseEnvOption("CASSETTE_MODE", &Env.CassetteMode, []string{"record", "replay"}, false)
+	parseEnvString("CASSETTE_DIR", &Env.CassetteDir, false)
 	parseEnvLevel("LOG_LEVEL", &Env.LogLevel, false)
 	parseEnvInt64Slice("WHITELIST", &Env.Whitelist, false)
 	parseEnvInt64Slice("ADMINS", &Env.Admins, false)
 	parseEnvBool("CACHING", &Env.Caching, false)
 	parseEnvString("CAPTIONS_HEADER", &Env.CaptionsHeader, false)
 	parseEnvString("CAPTIONS_DESCRIPTION", &Env.CaptionsDescription, false)
+	parseEnvBool("CAPTIONS_OVERFLOW", &Env.CaptionsOverflow, false)
 	parseEnvBool("DEFAULT_ENABLE_CAPTIONS", &Env.DefaultCaptions, false)
 	parseEnvBool("DEFAULT_ENABLE_SILENT", &Env.DefaultSilent, false)
 	parseEnvBool("DEFAULT_ENABLE_NSFW", &Env.DefaultNSFW, false)
 	parseEnvInt32Range("DEFAULT_MEDIA_ALBUM_LIMIT", &Env.DefaultMediaAlbumLimit, 1, 20, false)
 	parseEnvLanguage("DEFAULT_LANGUAGE", &Env.DefaultLanguage, false)
 	parseEnvBool("DEFAULT_DELETE_LINKS", &Env.DefaultDeleteLinks, false)
+	parseEnvBool("DEFAULT_FORMAT_PICKER", &Env.DefaultFormatPicker, false)
+	parseEnvInt32Range("DEFAULT_VIDEO_QUALITY", &Env.DefaultVideoQuality, 0, 4320, false)
 	parseEnvBool("AUTOMATIC_LANGUAGE_DETECTION", &Env.AutomaticLanguageDetection, false)
+
+	validateEnv()
+}
+
+// checks the settings that depend on each other
+func validateEnv() {
+	// the cloud bot api only accepts uploaded files
+	if Env.BotAPILocal && strings.TrimSuffix(Env.BotAPIURL, "/") == gotgbot.DefaultAPIURL {
+		logger.L.Fatalf("BOT_API_LOCAL requires BOT_API_URL to point to a self-hosted bot api server")
+	}
 }
 
 func GetDefaultConfig() *EnvConfig {
@@ -50,19 +85,35 @@ func GetDefaultConfig() *EnvConfig {
 		DBName: "govd",
 		DBUser: "govd",
 
-		BotAPIURL:         gotgbot.DefaultAPIURL,
-		ConcurrentUpdates: ext.DefaultMaxRoutines,
+		BotAPIURL:          gotgbot.DefaultAPIURL,
+		BotAPILocal:        false,
+		ConcurrentUpdates:  ext.DefaultMaxRoutines,
+		KeepPendingUpdates: false,
+
+		WebhookListenAddr: "0.0.0.0:8443",
+
+		JobWorkers:             10,
+		JobMaxAttempts:         3,
+		MaxChatJobs:            2,
+		MaxConcurrentDownloads: 10,
+		MaxLinksPerMessage:     5,
+		ShutdownTimeout:        time.Minute,
+
+		MaxCollectionEntries:     50,
+		CollectionConfirmEntries: 10,
 
 		DownloadsDirectory: "downloads",
 
 		MaxDuration: time.Hour,
 		MaxFileSize: 1000 * 1024 * 1024, // 1GB
 		RepoURL:     "https://github.com/govdbot/govd",
+		CassetteDir: "cassettes",
 		LogLevel:    zap.InfoLevel,
 		Caching:     true,
 
 		CaptionsHeader:      "<a href='{{url}}'>source</a> - @{{username}}",
 		CaptionsDescription: "<blockquote expandable>{{text}}</blockquote>",
+		CaptionsOverflow:    false,
 
 		DefaultCaptions:        true,
 		DefaultSilent:          false,
@@ -70,6 +121,8 @@ func GetDefaultConfig() *EnvConfig {
 		DefaultMediaAlbumLimit: 10,
 		DefaultLanguage:        "en",
 		DefaultDeleteLinks:     false,
+		DefaultFormatPicker:    false,
+		DefaultVideoQuality:    0,
 
 		AutomaticLanguageDetection: true,
 	}
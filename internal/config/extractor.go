@@ -10,6 +10,10 @@ import (
 
 const configPath = "private/config.yaml"
 
+// the section of the config file whose
+// plugins apply to every extractor
+const DefaultConfigKey = "default"
+
 var extractorConfigs map[string]*ExtractorConfig
 
 func loadFromConfig() {
@@ -52,6 +56,14 @@ func validateConfig() {
 		if len(cfg.Instance) > 0 && id != "youtube" {
 			logger.L.Fatalf("[%s] invalid config: custom instance is only supported for youtube extractor", id)
 		}
+		if cfg.TranscodeMaxDuration < 0 || cfg.TranscodeMaxSize < 0 {
+			logger.L.Fatalf("[%s] invalid config: transcode limits cannot be negative", id)
+		}
+		for _, p := range cfg.Plugins {
+			if p == nil || p.ID == "" {
+				logger.L.Fatalf("[%s] invalid config: plugins must have an id", id)
+			}
+		}
 		for _, r := range cfg.IgnoreRegex {
 			if r == nil {
 				logger.L.Fatalf("[%s] invalid config: ignore_regex contains invalid regex", id)
@@ -66,3 +78,9 @@ func GetExtractorConfig(extractorID string) *ExtractorConfig {
 	}
 	return &ExtractorConfig{}
 }
+
+// returns the configs of all the sections of the config file,
+// keyed by extractor id
+func GetExtractorConfigs() map[string]*ExtractorConfig {
+	return maps.Clone(extractorConfigs)
+}
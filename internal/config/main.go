@@ -1,6 +1,13 @@
 package config
 
 func Load() {
-	loadFromEnv()
+	loadFromEnv(true)
+	loadFromConfig()
+}
+
+// loads the configuration for running the extractors
+// alone, where the bot token is not needed
+func LoadStandalone() {
+	loadFromEnv(false)
 	loadFromConfig()
 }
@@ -14,9 +14,28 @@ type EnvConfig struct {
 	DBUser     string
 	DBPassword string
 
-	BotAPIURL         string
-	BotToken          string
-	ConcurrentUpdates int
+	BotAPIURL          string
+	BotAPILocal        bool
+	BotAPIDownloadsDir string
+	BotToken           string
+	ConcurrentUpdates  int
+	KeepPendingUpdates bool
+
+	WebhookURL        string
+	WebhookListenAddr string
+	WebhookSecret     string
+	WebhookCertFile   string
+	WebhookKeyFile    string
+
+	JobWorkers             int
+	JobMaxAttempts         int
+	MaxChatJobs            int
+	MaxConcurrentDownloads int
+	MaxLinksPerMessage     int
+	ShutdownTimeout        time.Duration
+
+	MaxCollectionEntries     int
+	CollectionConfirmEntries int
 
 	DownloadsDirectory string
 
@@ -27,6 +46,10 @@ type EnvConfig struct {
 	RepoURL      string
 	ProfilerPort int
 	MetricsPort  int
+	APIPort      int
+	APIKeys      []string
+	CassetteMode string
+	CassetteDir  string
 	LogLevel     zapcore.Level
 	Whitelist    []int64
 	Caching      bool
@@ -34,6 +57,7 @@ type EnvConfig struct {
 
 	CaptionsHeader      string
 	CaptionsDescription string
+	CaptionsOverflow    bool
 
 	DefaultCaptions        bool
 	DefaultSilent          bool
@@ -41,6 +65,8 @@ type EnvConfig struct {
 	DefaultMediaAlbumLimit int32
 	DefaultLanguage        string
 	DefaultDeleteLinks     bool
+	DefaultFormatPicker    bool
+	DefaultVideoQuality    int32
 
 	AutomaticLanguageDetection bool
 }
@@ -54,4 +80,20 @@ type ExtractorConfig struct {
 	Impersonate   bool             `yaml:"impersonate"`
 	IsDisabled    bool             `yaml:"disabled"`
 	Instance      []string         `yaml:"instance"`
+
+	Transcode            bool          `yaml:"transcode"`
+	TranscodeMaxDuration time.Duration `yaml:"transcode_max_duration"`
+	TranscodeMaxSize     int64         `yaml:"transcode_max_size"` // in MB
+
+	FitToLimit bool `yaml:"fit_to_limit"`
+
+	// post-processing plugins run on every downloaded file
+	Plugins []*PluginConfig `yaml:"plugins"`
+}
+
+type PluginConfig struct {
+	ID string `yaml:"id"`
+	// media types the plugin is applied to, all when empty
+	MediaTypes []string          `yaml:"media_types"`
+	Args       map[string]string `yaml:"args"`
 }
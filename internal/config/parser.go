@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -98,6 +99,32 @@ func parseEnvInt64Slice(env string, dest *[]int64, required bool) {
 	}
 }
 
+func parseEnvStringSlice(env string, dest *[]string, required bool) {
+	if value := os.Getenv(env); value != "" {
+		parts := strings.SplitSeq(value, ",")
+		for part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			*dest = append(*dest, part)
+		}
+	} else if required {
+		logger.L.Fatalf("%s env is not set", env)
+	}
+}
+
+func parseEnvOption(env string, dest *string, options []string, required bool) {
+	if value := os.Getenv(env); value != "" {
+		if !slices.Contains(options, value) {
+			logger.L.Fatalf("%s env must be one of: %s", env, strings.Join(options, ", "))
+		}
+		*dest = value
+	} else if required {
+		logger.L.Fatalf("%s env is not set", env)
+	}
+}
+
 func parseEnvInt32Range(env string, dest *int32, minVal int, maxVal int, required bool) {
 	if value := os.Getenv(env); value != "" {
 		if parsed, err := strconv.Atoi(value); err == nil {
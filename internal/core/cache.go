@@ -0,0 +1,177 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	mediaCacheSize = 1000
+	mediaCacheTTL  = time.Hour
+
+	failureCacheSize = 10000
+)
+
+// errors of content that won't be available for a while,
+// with how long they are cached. other errors (e.g.
+// timeouts) are not cached, as retrying may succeed.
+var failureTTLs = []struct {
+	err error
+	ttl time.Duration
+}{
+	{util.ErrUnavailable, time.Hour},
+	{util.ErrPaidContent, time.Hour},
+	{util.ErrGeoRestrictedContent, time.Hour},
+	{util.ErrAgeRestricted, 30 * time.Minute},
+	{util.ErrAuthenticationNeeded, 30 * time.Minute},
+	{ErrNoMedia, 30 * time.Minute},
+}
+
+type cachedFailure struct {
+	err       error
+	expiresAt time.Time
+}
+
+var (
+	// stored media, keyed by content and audio mode
+	mediaCache = expirable.NewLRU[string, *models.Media](mediaCacheSize, nil, mediaCacheTTL)
+	// failed extractions, keyed by content. the lru expires
+	// entries after the longest ttl, shorter ones are checked
+	// when the entry is read
+	failureCache = expirable.NewLRU[string, *cachedFailure](failureCacheSize, nil, maxFailureTTL())
+)
+
+var cacheRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "govd",
+		Name:      "cache_requests_total",
+		Help:      "Number of media cache lookups, by cache and result.",
+	},
+	[]string{
+		"cache",
+		"result",
+	},
+)
+
+func maxFailureTTL() time.Duration {
+	var ttl time.Duration
+	for _, f := range failureTTLs {
+		ttl = max(ttl, f.ttl)
+	}
+	return ttl
+}
+
+func observeCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+func mediaCacheKey(ctx *models.ExtractorContext) string {
+	if ctx.AudioOnly {
+		return ctx.Key() + "#audio"
+	}
+	return ctx.Key()
+}
+
+// returns the stored media of the content, from
+// memory when it was looked up recently
+func getStoredMedia(ctx *models.ExtractorContext) (*models.Media, error) {
+	key := mediaCacheKey(ctx)
+	if media, ok := mediaCache.Get(key); ok {
+		observeCache("memory", true)
+		return media, nil
+	}
+	observeCache("memory", false)
+
+	mediaRow, err := database.Q().GetMediaByContentID(
+		ctx.Context,
+		database.GetMediaByContentIDParams{
+			ExtractorID: ctx.Extractor.ID,
+			ContentID:   ctx.ContentID,
+		},
+	)
+	if err != nil {
+		observeCache("database", false)
+		return nil, err
+	}
+	media, err := ParseStoredMedia(
+		ctx.Context, ctx.Extractor,
+		&mediaRow, ctx.AudioOnly,
+	)
+	if err != nil {
+		observeCache("database", false)
+		return nil, err
+	}
+	observeCache("database", true)
+
+	mediaCache.Add(key, media)
+	return media, nil
+}
+
+// returns the error of the last extraction
+// of the content, if it is still cached
+func getCachedFailure(ctx *models.ExtractorContext) error {
+	failure, ok := failureCache.Get(ctx.Key())
+	if ok && time.Now().After(failure.expiresAt) {
+		failureCache.Remove(ctx.Key())
+		ok = false
+	}
+	observeCache("failure", ok)
+	if !ok {
+		return nil
+	}
+	return failure.err
+}
+
+// caches the error of the extraction,
+// when it is not expected to change soon
+func cacheFailure(ctx *models.ExtractorContext, err error) {
+	for _, f := range failureTTLs {
+		if errors.Is(err, f.err) {
+			failureCache.Add(ctx.Key(), &cachedFailure{
+				err:       err,
+				expiresAt: time.Now().Add(f.ttl),
+			})
+			return
+		}
+	}
+}
+
+// removes the stored media of the content from memory,
+// so that the formats stored since are looked up again
+func invalidateMedia(ctx *models.ExtractorContext) {
+	mediaCache.Remove(ctx.Key())
+	mediaCache.Remove(ctx.Key() + "#audio")
+}
+
+// removes every cached entry of the content, including
+// the stored media. returns whether anything was removed.
+func PurgeMedia(ctx context.Context, extractorCtx *models.ExtractorContext) (bool, error) {
+	key := extractorCtx.Key()
+	purged := mediaCache.Remove(key)
+	purged = mediaCache.Remove(key+"#audio") || purged
+	purged = failureCache.Remove(key) || purged
+
+	rows, err := database.Q().DeleteMediaByContentID(
+		ctx,
+		database.DeleteMediaByContentIDParams{
+			ExtractorID: extractorCtx.Extractor.ID,
+			ContentID:   extractorCtx.ContentID,
+		},
+	)
+	if err != nil {
+		return purged, err
+	}
+	return purged || rows > 0, nil
+}
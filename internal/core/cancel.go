@@ -0,0 +1,86 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/govdbot/govd/internal/models"
+)
+
+type activeTask struct {
+	chatID       int64
+	userID       int64
+	extractorCtx *models.ExtractorContext
+}
+
+// tasks that can be canceled by the user, by task id
+var (
+	activeTasks   = make(map[string]*activeTask)
+	activeTasksMu sync.Mutex
+)
+
+func registerTask(chatID int64, userID int64, extractorCtx *models.ExtractorContext) string {
+	taskID := uuid.NewString()[:8]
+
+	activeTasksMu.Lock()
+	defer activeTasksMu.Unlock()
+
+	activeTasks[taskID] = &activeTask{
+		chatID:       chatID,
+		userID:       userID,
+		extractorCtx: extractorCtx,
+	}
+	return taskID
+}
+
+func unregisterTask(taskID string) {
+	activeTasksMu.Lock()
+	defer activeTasksMu.Unlock()
+
+	delete(activeTasks, taskID)
+}
+
+// returns the id of the user who requested the task
+func GetTaskUser(taskID string) (int64, bool) {
+	activeTasksMu.Lock()
+	defer activeTasksMu.Unlock()
+
+	task, ok := activeTasks[taskID]
+	if !ok {
+		return 0, false
+	}
+	return task.userID, true
+}
+
+func CancelTask(taskID string) bool {
+	activeTasksMu.Lock()
+	defer activeTasksMu.Unlock()
+
+	task, ok := activeTasks[taskID]
+	if !ok {
+		return false
+	}
+	task.extractorCtx.CancelFunc()
+	return true
+}
+
+// cancels the tasks of the user in the chat,
+// or every task in the chat if userID is zero.
+// returns the number of canceled tasks.
+func CancelChatTasks(chatID int64, userID int64) int {
+	activeTasksMu.Lock()
+	defer activeTasksMu.Unlock()
+
+	var count int
+	for _, task := range activeTasks {
+		if task.chatID != chatID {
+			continue
+		}
+		if userID != 0 && task.userID != userID {
+			continue
+		}
+		task.extractorCtx.CancelFunc()
+		count++
+	}
+	return count
+}
@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+)
+
+var (
+	// {{name}}
+	placeholderPattern = regexp.MustCompile(`\{\{(\w+)\}\}`)
+	// {{#name}}, shown when the value is set, or
+	// {{^name}}, shown when it is not. both end with {{/name}}
+	sectionPattern = regexp.MustCompile(`\{\{([#^])(\w+)\}\}`)
+	// any of the above, including the end of sections
+	tokenPattern = regexp.MustCompile(`\{\{([#^/]?)(\w+)\}\}`)
+)
+
+// the longest caption template a chat can set
+const maxCaptionTemplateLength = 512
+
+// media used to preview the caption templates
+var previewMedia = &models.Media{
+	ContentURL:   "https://example.com/post",
+	Caption:      "this is the text of the post",
+	Title:        "post title",
+	AuthorName:   "Author",
+	AuthorHandle: "author",
+	UploadedAt:   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+	LikeCount:    1234,
+	ViewCount:    56789,
+	CommentCount: 42,
+}
+
+func formatCaption(media *models.Media, username string, chat *database.GetOrCreateChatRow) string {
+	header, description := CaptionTemplates(chat)
+	return renderCaption(media, username, header, description, chat.Captions)
+}
+
+// returns the caption of a sample media with the given
+// templates, showing how the captions of the chat will look
+func PreviewCaption(username string, header string, description string) string {
+	return renderCaption(previewMedia, username, header, description, true)
+}
+
+// returns the caption templates of the chat, falling
+// back to the global ones when the chat has none
+func CaptionTemplates(chat *database.GetOrCreateChatRow) (string, string) {
+	header := config.Env.CaptionsHeader
+	if chat.CaptionHeader.Valid {
+		header = chat.CaptionHeader.String
+	}
+	description := config.Env.CaptionsDescription
+	if chat.CaptionDescription.Valid {
+		description = chat.CaptionDescription.String
+	}
+	return header, description
+}
+
+// returns the names of the placeholders of the caption templates
+func CaptionPlaceholders() []string {
+	names := captionValues(&models.Media{}, "", "")
+	return slices.Sorted(maps.Keys(names))
+}
+
+// reports whether the caption template only uses known
+// placeholders, closed sections and html supported by telegram
+func ValidateCaptionTemplate(template string) error {
+	if utf8.RuneCountInString(template) > maxCaptionTemplateLength {
+		return fmt.Errorf("template longer than %d characters", maxCaptionTemplateLength)
+	}
+	names := captionValues(&models.Media{}, "", "")
+
+	var open []string
+	matches := tokenPattern.FindAllStringSubmatch(template, -1)
+	for _, match := range matches {
+		kind, name := match[1], match[2]
+		if _, ok := names[name]; !ok {
+			return fmt.Errorf("unknown placeholder: %s", match[0])
+		}
+		switch kind {
+		case "#", "^":
+			open = append(open, name)
+		case "/":
+			if len(open) == 0 || open[len(open)-1] != name {
+				return fmt.Errorf("unexpected end of section: %s", match[0])
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Errorf("unclosed section: {{#%s}}", open[len(open)-1])
+	}
+	if strings.Count(template, "{{") != len(matches) {
+		return fmt.Errorf("malformed placeholder")
+	}
+
+	return util.ValidateTelegramHTML(template)
+}
+
+func renderCaption(
+	media *models.Media,
+	username string,
+	header string,
+	description string,
+	isEnabled bool,
+) string {
+	// the caption is cut to the telegram
+	// limit only when it is sent
+	values := captionValues(media, username, media.Caption)
+
+	header = renderTemplate(header, values)
+	if isEnabled && media.Caption != "" {
+		description = renderTemplate(description, values)
+	} else {
+		description = ""
+	}
+	return header + "\n" + description
+}
+
+// returns the values of the placeholders of the caption
+// templates. unknown details of the media are left empty,
+// so that the sections using them are hidden.
+func captionValues(media *models.Media, username string, caption string) map[string]string {
+	values := map[string]string{
+		"username": username,
+		"url":      util.EscapeHTML(media.ContentURL),
+		"text":     util.EscapeHTML(caption),
+		"title":    util.EscapeHTML(media.Title),
+		"author":   util.EscapeHTML(media.AuthorName),
+		"handle":   util.EscapeHTML(media.AuthorHandle),
+		"likes":    formatCount(media.LikeCount),
+		"views":    formatCount(media.ViewCount),
+		"comments": formatCount(media.CommentCount),
+		"date":     "",
+	}
+	if values["author"] == "" {
+		values["author"] = values["handle"]
+	}
+	if !media.UploadedAt.IsZero() {
+		values["date"] = media.UploadedAt.UTC().Format("2006-01-02")
+	}
+	return values
+}
+
+// replaces the placeholders of the template with their values
+// and keeps the sections whose condition is met. placeholders
+// and sections with unknown names are left as they are.
+func renderTemplate(template string, values map[string]string) string {
+	var sb strings.Builder
+	for {
+		loc := sectionPattern.FindStringSubmatchIndex(template)
+		if loc == nil {
+			break
+		}
+		inverted := template[loc[2]:loc[3]] == "^"
+		name := template[loc[4]:loc[5]]
+		closing := "{{/" + name + "}}"
+		end := strings.Index(template[loc[1]:], closing)
+		if _, known := values[name]; !known || end < 0 {
+			sb.WriteString(replacePlaceholders(template[:loc[1]], values))
+			template = template[loc[1]:]
+			continue
+		}
+		sb.WriteString(replacePlaceholders(template[:loc[0]], values))
+		body := template[loc[1] : loc[1]+end]
+		if (values[name] != "") != inverted {
+			sb.WriteString(renderTemplate(body, values))
+		}
+		template = template[loc[1]+end+len(closing):]
+	}
+	sb.WriteString(replacePlaceholders(template, values))
+	return sb.String()
+}
+
+func replacePlaceholders(s string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		value, ok := values[match[2:len(match)-2]]
+		if !ok {
+			return match
+		}
+		return value
+	})
+}
+
+// returns the count in a short form, e.g. 1.2K
+// or 3.4M. zero counts are considered unknown.
+func formatCount(n int64) string {
+	switch {
+	case n <= 0:
+		return ""
+	case n < 1_000:
+		return strconv.FormatInt(n, 10)
+	case n < 1_000_000:
+		return shortCount(float64(n)/1_000) + "K"
+	case n < 1_000_000_000:
+		return shortCount(float64(n)/1_000_000) + "M"
+	default:
+		return shortCount(float64(n)/1_000_000_000) + "B"
+	}
+}
+
+func shortCount(v float64) string {
+	if v >= 100 {
+		return strconv.FormatFloat(v, 'f', 0, 64)
+	}
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	return strings.TrimSuffix(s, ".0")
+}
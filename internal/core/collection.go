@@ -0,0 +1,27 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+// returned by the download task when the link is a collection
+// (playlist, album, profile) instead of a single media.
+// its entries are expected to be downloaded as separate tasks.
+type CollectionError struct {
+	Entries []string
+}
+
+func (e *CollectionError) Error() string {
+	return fmt.Sprintf("collection with %d entries", len(e.Entries))
+}
+
+// returns the entries of the collection
+// if the error is a CollectionError, nil otherwise
+func CollectionEntries(err error) []string {
+	var collectionErr *CollectionError
+	if errors.As(err, &collectionErr) {
+		return collectionErr.Entries
+	}
+	return nil
+}
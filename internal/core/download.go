@@ -2,10 +2,12 @@ package core
 
 import (
 	"fmt"
+	"slices"
 	"sync"
 
 	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/plugins"
 	"github.com/govdbot/govd/internal/util"
 	"github.com/govdbot/govd/internal/util/download"
 )
@@ -28,6 +30,11 @@ func downloadMediaFormats(
 			defer wg.Done()
 			semaphore <- struct{}{}        // acquire
 			defer func() { <-semaphore }() // release
+			if err := acquireDownloadSlot(ctx.Context); err != nil {
+				formats <- &models.DownloadedFormat{Index: index, Error: err}
+				return
+			}
+			defer releaseDownloadSlot()
 			downloadItem(ctx, formats, media.Items[index], index)
 		}(i)
 	}
@@ -59,7 +66,7 @@ func downloadItem(
 	case 1:
 		format = item.Formats[0]
 	default:
-		format = item.GetDefaultFormat()
+		format = selectFormat(ctx, item)
 	}
 
 	if format == nil {
@@ -72,10 +79,7 @@ func downloadItem(
 
 	ctx.Debugf("selected format: %s", format.ToString())
 
-	// validate format before download
-	// to avoid downloading large files
-	// or unsupported formats
-	err := validateFormat(format)
+	downloadedFormat, err := processFormat(ctx, item, format, index)
 	if err != nil {
 		formats <- &models.DownloadedFormat{
 			Index: index,
@@ -83,46 +87,128 @@ func downloadItem(
 		}
 		return
 	}
+	formats <- downloadedFormat
+}
+
+// downloads every item of the media, selecting the formats
+// as the download tasks do. used outside of the bot.
+func DownloadMediaFormats(
+	ctx *models.ExtractorContext,
+	media *models.Media,
+) ([]*models.DownloadedFormat, error) {
+	return downloadMediaFormats(ctx, media)
+}
+
+// downloads a single format of the item, with the same limits and
+// processing as the download tasks. used outside of the bot.
+func DownloadItemFormat(
+	ctx *models.ExtractorContext,
+	item *models.MediaItem,
+	format *models.MediaFormat,
+) (*models.DownloadedFormat, error) {
+	if err := acquireDownloadSlot(ctx.Context); err != nil {
+		return nil, err
+	}
+	defer releaseDownloadSlot()
+
+	return processFormat(ctx, item, format, 0)
+}
+
+// downloads the format and runs the processing steps on it
+func processFormat(
+	ctx *models.ExtractorContext,
+	item *models.MediaItem,
+	format *models.MediaFormat,
+	index int,
+) (*models.DownloadedFormat, error) {
+	// validate format before download
+	// to avoid downloading large files
+	// or unsupported formats
+	if err := ValidateFormat(ctx, format); err != nil {
+		return nil, err
+	}
 
 	downloadedFormat, err := downloadFormat(ctx, index, format)
 	if err != nil {
-		formats <- &models.DownloadedFormat{
-			Index: index,
-			Error: err,
-		}
-		return
+		return nil, err
 	}
 
 	// validate format again after download
 	// in case metadata extraction is done
 	// after download
-	err = validateFormat(format)
-	if err != nil {
-		formats <- &models.DownloadedFormat{
-			Index: index,
-			Error: err,
-		}
-		return
+	if err := ValidateFormat(ctx, format); err != nil {
+		return nil, err
 	}
 
 	// merge audio into video if needed
 	mergeFormats(item, downloadedFormat)
 
-	for _, plugin := range format.Plugins {
-		if plugin != nil {
-			ctx.Debugf("running plugin: %s", plugin.ID)
-			err := plugin.RunFunc(ctx, item, downloadedFormat)
-			if err != nil {
-				formats <- &models.DownloadedFormat{
-					Index: index,
-					Error: fmt.Errorf("plugin %s failed: %w", plugin.ID, err),
-				}
-				return
-			}
+	// extract audio from video if needed
+	extractAudio(ctx, downloadedFormat)
+
+	// transcode to a playable format if needed
+	transcodeFormat(ctx, downloadedFormat)
+
+	// re-encode to fit the size limits if needed
+	fitFormat(ctx, downloadedFormat)
+
+	// embed title, artist and cover art if needed
+	tagFormat(ctx, downloadedFormat)
+
+	// plugins attached by the extractor and by
+	// the config file, run in the order of their stage
+	chain := plugins.Sort(slices.Concat(
+		format.Plugins,
+		plugins.Configured(ctx.Extractor.ID),
+	))
+	for _, plugin := range chain {
+		if plugin == nil {
+			continue
+		}
+		// a previous plugin may have changed the media type
+		if !plugin.Accepts(downloadedFormat.Format.Type) {
+			ctx.Debugf("skipping plugin: %s", plugin.ID)
+			continue
+		}
+		ctx.Debugf("running plugin: %s", plugin.ID)
+		err := plugin.RunFunc(ctx, item, downloadedFormat)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %s failed: %w", plugin.ID, err)
+		}
+		if plugin.Output != "" {
+			downloadedFormat.Format.Type = plugin.Output
 		}
 	}
 
-	formats <- downloadedFormat
+	return downloadedFormat, nil
+}
+
+func selectFormat(
+	ctx *models.ExtractorContext,
+	item *models.MediaItem,
+) *models.MediaFormat {
+	if ctx.FormatID != "" {
+		format := item.GetFormatByID(ctx.FormatID)
+		if format != nil {
+			return format
+		}
+	}
+	if ctx.AudioOnly {
+		format := item.GetDefaultAudioFormat()
+		if format != nil {
+			return format
+		}
+	}
+	format := item.GetVideoFormatWithLimit(
+		videoQuality(ctx),
+		func(format *models.MediaFormat) bool {
+			return validateFormat(ctx, format) == nil
+		},
+	)
+	if format != nil {
+		return format
+	}
+	return item.GetDefaultFormat()
 }
 
 func downloadFormat(
@@ -1,7 +1,9 @@
 package core
 
 import (
+	"context"
 	"errors"
+	"html"
 	"strings"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
@@ -19,47 +21,92 @@ func HandleError(
 	extractorCtx *models.ExtractorContext,
 	err error,
 ) {
-	chat := extractorCtx.Chat
-	localizer := localization.New(chat.Language)
+	message, errorID := ErrorMessage(extractorCtx, err)
+	if message == "" {
+		return
+	}
+	sendErrorMessage(b, ctx, errorID, message)
+}
+
+// the error of one of the links of a message,
+// as returned by ErrorMessage
+type LinkError struct {
+	URL     string
+	Message string
+	ErrorID string
+}
+
+// reports the errors of the links of a message
+// in a single message, one line per link.
+// the url is omitted when empty.
+func HandleLinkErrors(
+	b *gotgbot.Bot,
+	ctx *ext.Context,
+	linkErrors []*LinkError,
+) {
+	lines := make([]string, 0, len(linkErrors))
+	for _, linkError := range linkErrors {
+		if linkError.Message == "" {
+			continue
+		}
+		message := linkError.Message
+		if linkError.URL != "" {
+			message = html.EscapeString(linkError.URL) + ": " + message
+		}
+		lines = append(lines, formatErrorMessage(
+			ctx, message,
+			linkError.ErrorID,
+		))
+	}
+	if len(lines) == 0 {
+		return
+	}
+	sendMessage(b, ctx, strings.Join(lines, "\n"))
+}
+
+// returns the localized message describing the error and, for
+// unexpected errors, the id it was logged with. the message is
+// empty for errors that should not be reported to the user.
+func ErrorMessage(
+	extractorCtx *models.ExtractorContext,
+	err error,
+) (string, string) {
+	localizer := localization.New(extractorCtx.Chat.Language)
+
+	if IsCanceled(extractorCtx) {
+		return localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.DownloadCanceledMessage.ID,
+		}), ""
+	}
+
+	// collections are only expanded by jobs
+	if CollectionEntries(err) != nil {
+		err = util.ErrCollectionUnsupported
+	}
 
 	botError := asBotError(err)
 	if botError != nil {
-		sendErrorMessage(
-			b, ctx, "",
-			localizer.T(&i18n.LocalizeConfig{
-				MessageID: botError.ID,
-			}),
-		)
-		return
+		return localizer.T(&i18n.LocalizeConfig{
+			MessageID: botError.ID,
+		}), ""
 	}
 
 	if errors.Is(err, ErrNoMedia) {
-		return
+		return "", ""
 	}
 	if isChatWriteForbidden(err) {
-		return
+		return "", ""
 	}
 	if isPermissionDenied(err) {
-		sendErrorMessage(
-			b, ctx, "",
-			localizer.T(&i18n.LocalizeConfig{
-				MessageID: localization.ErrorPermissionDenied.ID,
-			}),
-		)
-		return
+		return localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.ErrorPermissionDenied.ID,
+		}), ""
 	}
 
 	errorID := util.HashedError(err)
 
 	extractorCtx.Errorf("unexpected error: [%s] %v", errorID, err)
 
-	sendErrorMessage(
-		b, ctx, errorID,
-		localizer.T(&i18n.LocalizeConfig{
-			MessageID: localization.ErrorMessage.ID,
-		}),
-	)
-
 	database.Q().LogError(
 		extractorCtx.Context,
 		database.LogErrorParams{
@@ -67,6 +114,36 @@ func HandleError(
 			Message: err.Error(),
 		},
 	)
+
+	return localizer.T(&i18n.LocalizeConfig{
+		MessageID: localization.ErrorMessage.ID,
+	}), errorID
+}
+
+// reports whether the task was canceled by the user
+func IsCanceled(extractorCtx *models.ExtractorContext) bool {
+	return errors.Is(extractorCtx.Context.Err(), context.Canceled)
+}
+
+// reports whether the task may succeed if retried.
+// known errors and cancellations by the user are final.
+func IsRetryableError(extractorCtx *models.ExtractorContext, err error) bool {
+	if IsCanceled(extractorCtx) {
+		return false
+	}
+	if asBotError(err) != nil || errors.Is(err, ErrNoMedia) {
+		return false
+	}
+	if errors.Is(err, ErrPartiallySent) {
+		return false
+	}
+	if CollectionEntries(err) != nil {
+		return false
+	}
+	if isChatWriteForbidden(err) || isPermissionDenied(err) {
+		return false
+	}
+	return true
 }
 
 func isChatWriteForbidden(err error) bool {
@@ -92,7 +169,7 @@ func asBotError(err error) *util.Error {
 func formatErrorMessage(ctx *ext.Context, message string, errorID string) string {
 	var suffix string
 	if errorID != "" {
-		if ctx.CallbackQuery != nil || ctx.InlineQuery != nil {
+		if ctx.InlineQuery != nil {
 			suffix = " [" + errorID + "]"
 		} else {
 			suffix = " [<code>" + errorID + "</code>]"
@@ -107,16 +184,37 @@ func sendErrorMessage(
 	errroID string,
 	message string,
 ) {
-	message = formatErrorMessage(ctx, message, errroID)
+	sendMessage(b, ctx, formatErrorMessage(ctx, message, errroID))
+}
 
+// replies to the update with the message, in
+// the way that fits the update type
+func sendMessage(
+	b *gotgbot.Bot,
+	ctx *ext.Context,
+	message string,
+) {
 	switch {
 	case ctx.Message != nil:
-		ctx.EffectiveMessage.Reply(b, message, nil)
+		ctx.EffectiveMessage.Reply(
+			b, message,
+			&gotgbot.SendMessageOpts{
+				LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+					IsDisabled: true,
+				},
+			},
+		)
 	case ctx.CallbackQuery != nil:
-		ctx.CallbackQuery.Answer(b, &gotgbot.AnswerCallbackQueryOpts{
-			Text:      message,
-			ShowAlert: true,
-		})
+		// callback query is already answered
+		// when the task starts, so edit its message
+		ctx.EffectiveMessage.EditText(
+			b, message,
+			&gotgbot.EditMessageTextOpts{
+				LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+					IsDisabled: true,
+				},
+			},
+		)
 	case ctx.InlineQuery != nil:
 		ctx.InlineQuery.Answer(b, nil,
 			&gotgbot.AnswerInlineQueryOpts{
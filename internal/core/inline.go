@@ -73,25 +73,52 @@ func HandleInlineResultTask(
 	ctx *ext.Context,
 	extractorCtx *models.ExtractorContext,
 ) error {
+	untrack, err := trackTask(extractorCtx)
+	if err != nil {
+		return err
+	}
+	defer untrack()
 	defer extractorCtx.FilesTracker.Cleanup()
 
-	key := extractorCtx.Key()
-
-	acquireQueue(key)
-	defer releaseQueue(key)
-
-	taskResult, err := executeDownload(extractorCtx, true)
+	taskResult, shared, err := shareTask(
+		extractorCtx.Context,
+		flightKey(extractorCtx),
+		func() (*models.TaskResult, error) {
+			taskResult, err := executeDownload(extractorCtx, true)
+			if err != nil {
+				return nil, err
+			}
+			messages, err := sendInlineTaskResult(bot, ctx, extractorCtx, taskResult)
+			if err != nil {
+				return nil, err
+			}
+			return sentTaskResult(taskResult, messages), nil
+		},
+	)
 	if err != nil {
 		return err
 	}
+	if shared {
+		_, err = sendInlineTaskResult(bot, ctx, extractorCtx, taskResult)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
+func sendInlineTaskResult(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	extractorCtx *models.ExtractorContext,
+	taskResult *models.TaskResult,
+) ([]gotgbot.Message, error) {
 	caption := formatCaption(
 		taskResult.Media,
 		bot.Username,
-		extractorCtx.Chat.Captions,
+		extractorCtx.Chat,
 	)
-
-	err = SendInlineFormats(
+	return SendInlineFormats(
 		bot, ctx, extractorCtx,
 		taskResult.Media, taskResult.Formats,
 		&models.SendFormatsOptions{
@@ -99,11 +126,6 @@ func HandleInlineResultTask(
 			IsStored: taskResult.IsStored,
 		},
 	)
-	if err != nil {
-		return err
-	}
-
-	return nil
 }
 
 func AddTask(taskID string, ctx *models.ExtractorContext) bool {
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"slices"
+
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/PaulSonOfLars/gotgbot/v2/ext"
 	"github.com/govdbot/govd/internal/config"
@@ -14,29 +16,85 @@ func HandleDownloadTask(
 	ctx *ext.Context,
 	extractorCtx *models.ExtractorContext,
 ) error {
+	untrack, err := trackTask(extractorCtx)
+	if err != nil {
+		return err
+	}
+	defer untrack()
 	defer extractorCtx.FilesTracker.Cleanup()
 
-	key := extractorCtx.Key()
+	message := ctx.EffectiveMessage
+
+	var userID int64
+	if ctx.EffectiveUser != nil {
+		userID = ctx.EffectiveUser.Id
+	}
+	taskID := registerTask(message.Chat.Id, userID, extractorCtx)
+	defer unregisterTask(taskID)
 
-	acquireQueue(key)
-	defer releaseQueue(key)
+	// the task may have been canceled while queued
+	if err := extractorCtx.Context.Err(); err != nil {
+		return err
+	}
 
-	message := ctx.EffectiveMessage
+	progress := startProgress(bot, extractorCtx, taskID, message)
+	defer progress.stop()
 	isSpoiler := util.HasHashtagEntity(message, "spoiler") ||
 		util.HasHashtagEntity(message, "nsfw")
 
-	taskResult, err := executeDownload(extractorCtx, false)
+	usePicker := extractorCtx.Chat.FormatPicker || util.HasHashtagEntity(message, "pick")
+	if usePicker && !extractorCtx.AudioOnly {
+		taskResult, err := executePicker(bot, ctx, extractorCtx, isSpoiler)
+		if err != nil {
+			return err
+		}
+		if taskResult == nil {
+			// waiting for the user to pick a format
+			return nil
+		}
+		_, err = sendTaskResult(bot, ctx, extractorCtx, taskResult, isSpoiler)
+		return err
+	}
+
+	taskResult, shared, err := shareTask(
+		extractorCtx.Context,
+		flightKey(extractorCtx),
+		func() (*models.TaskResult, error) {
+			taskResult, err := executeDownload(extractorCtx, false)
+			if err != nil {
+				return nil, err
+			}
+			messages, err := sendTaskResult(bot, ctx, extractorCtx, taskResult, isSpoiler)
+			if err != nil {
+				return nil, err
+			}
+			return sentTaskResult(taskResult, messages), nil
+		},
+	)
 	if err != nil {
 		return err
 	}
+	if shared {
+		extractorCtx.Debugf("sharing the result of a running task")
+		_, err = sendTaskResult(bot, ctx, extractorCtx, taskResult, isSpoiler)
+		return err
+	}
+	return nil
+}
 
+func sendTaskResult(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	extractorCtx *models.ExtractorContext,
+	taskResult *models.TaskResult,
+	isSpoiler bool,
+) ([]gotgbot.Message, error) {
 	caption := formatCaption(
 		taskResult.Media,
 		bot.Username,
-		extractorCtx.Chat.Captions,
+		extractorCtx.Chat,
 	)
-
-	_, err = SendFormats(
+	return SendFormats(
 		bot, ctx, extractorCtx,
 		taskResult.Media, taskResult.Formats,
 		&models.SendFormatsOptions{
@@ -45,15 +103,11 @@ func HandleDownloadTask(
 			IsStored:  taskResult.IsStored,
 		},
 	)
-	if err != nil {
-		return err
-	}
-	return nil
 }
 
-// performs the actual download operation
-// this function is wrapped by singleflight
-// to prevent duplicate downloads
+// performs the actual download operation, reusing
+// the stored media when possible. callers share the
+// result through shareTask to prevent duplicate downloads
 func executeDownload(extractorCtx *models.ExtractorContext, isInline bool) (*models.TaskResult, error) {
 	if config.Env.Caching {
 		task, err := taskFromDatabase(extractorCtx)
@@ -72,58 +126,86 @@ func executeDownload(extractorCtx *models.ExtractorContext, isInline bool) (*mod
 			return task, nil
 		}
 	}
+	media, err := extractMedia(extractorCtx)
+	if err != nil {
+		return nil, err
+	}
+	return downloadMedia(extractorCtx, media, isInline)
+}
+
+func extractMedia(extractorCtx *models.ExtractorContext) (*models.Media, error) {
+	// content that failed recently is not extracted again
+	if err := getCachedFailure(extractorCtx); err != nil {
+		extractorCtx.Debugf("cached failure: %v", err)
+		return nil, err
+	}
+
+	extractorCtx.ReportProgress(models.ProgressPhaseExtracting, 0, 0)
+
+	media, err := runExtractor(extractorCtx)
+	if err != nil {
+		cacheFailure(extractorCtx, err)
+		return nil, err
+	}
+	return media, nil
+}
+
+func runExtractor(extractorCtx *models.ExtractorContext) (*models.Media, error) {
 	resp, err := extractorCtx.Extractor.GetFunc(extractorCtx)
 	if err != nil {
 		return nil, err
 	}
+	if len(resp.Entries) > 0 {
+		return nil, &CollectionError{Entries: resp.Entries}
+	}
 	if resp.Media == nil || len(resp.Media.Items) == 0 {
 		// no media extracted (e.g. text only post)
 		return nil, ErrNoMedia
 	}
+	return resp.Media, nil
+}
 
-	if isInline && len(resp.Media.Items) > 1 {
+func downloadMedia(
+	extractorCtx *models.ExtractorContext,
+	media *models.Media,
+	isInline bool,
+) (*models.TaskResult, error) {
+	if isInline && len(media.Items) > 1 {
 		return nil, util.ErrInlineMediaAlbum
 	}
-	err = checkAlbumLimit(
-		len(resp.Media.Items),
+	err := checkAlbumLimit(
+		len(media.Items),
 		extractorCtx.Chat,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	formats, err := downloadMediaFormats(extractorCtx, resp.Media)
+	formats, err := downloadMediaFormats(extractorCtx, media)
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.TaskResult{
-		Media:   resp.Media,
+		Media:   media,
 		Formats: formats,
 	}, nil
 }
 
 func taskFromDatabase(ctx *models.ExtractorContext) (*models.TaskResult, error) {
-	mediaRow, err := database.Q().GetMediaByContentID(
-		ctx.Context,
-		database.GetMediaByContentIDParams{
-			ExtractorID: ctx.Extractor.ID,
-			ContentID:   ctx.ContentID,
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	media, err := ParseStoredMedia(ctx.Context, ctx.Extractor, &mediaRow)
+	media, err := getStoredMedia(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	formats := make([]*models.DownloadedFormat, 0, len(media.Items))
 	for i, item := range media.Items {
+		format := selectStoredFormat(ctx, item)
+		if format == nil {
+			return nil, errNoStoredFormat
+		}
 		formats = append(formats, &models.DownloadedFormat{
-			Format: item.Formats[0],
+			Format: format,
 			Index:  i,
 		})
 	}
@@ -135,6 +217,52 @@ func taskFromDatabase(ctx *models.ExtractorContext) (*models.TaskResult, error)
 	}, nil
 }
 
+// returns the stored format of the item matching the
+// request, or nil when it has to be downloaded instead
+func selectStoredFormat(ctx *models.ExtractorContext, item *models.MediaItem) *models.MediaFormat {
+	if ctx.FormatID != "" {
+		return item.GetFormatByID(ctx.FormatID)
+	}
+	if ctx.AudioOnly {
+		if format := item.GetDefaultAudioFormat(); format != nil {
+			return format
+		}
+	}
+	videos := item.FilterFormats(func(format *models.MediaFormat) bool {
+		return format.Type == database.MediaTypeVideo
+	})
+	if len(videos) == 0 {
+		return item.GetDefaultFormat()
+	}
+	// the stored formats are only some of the available ones,
+	// so a video is reused only when it is the one selectFormat
+	// picked for the same quality limit
+	quality := videoQuality(ctx)
+	for _, format := range videos {
+		if slices.Contains(format.SelectedFor, quality) {
+			return format
+		}
+	}
+	return nil
+}
+
+// returns the quality limits the formats sent for the
+// request are stored with, none when chosen by the user
+func selectedFor(ctx *models.ExtractorContext) []int32 {
+	if ctx.FormatID != "" {
+		return []int32{}
+	}
+	return []int32{videoQuality(ctx)}
+}
+
+// returns the preferred video quality of the chat, 0 for the best
+func videoQuality(ctx *models.ExtractorContext) int32 {
+	if ctx.Chat == nil {
+		return 0
+	}
+	return ctx.Chat.VideoQuality
+}
+
 func checkAlbumLimit(n int, chat *database.GetOrCreateChatRow) error {
 	if chat.Type == database.ChatTypeGroup {
 		if n > int(chat.MediaAlbumLimit) {
@@ -149,10 +277,23 @@ func checkAlbumLimit(n int, chat *database.GetOrCreateChatRow) error {
 	return nil
 }
 
-func validateFormat(fmt *models.MediaFormat) error {
+// reports whether the format is within the size and duration
+// limits, or can be re-encoded to fit them when allowed
+func ValidateFormat(ctx *models.ExtractorContext, format *models.MediaFormat) error {
+	err := validateFormat(ctx, format)
+	if err != nil && canFitToLimit(ctx, format, err) {
+		return nil
+	}
+	return err
+}
+
+func validateFormat(ctx *models.ExtractorContext, fmt *models.MediaFormat) error {
 	if util.ExceedsMaxFileSize(fmt.FileSize) {
 		return util.ErrFileTooLarge
 	}
+	if !ctx.Standalone && util.ExceedsTelegramFileSize(fmt.FileSize) {
+		return util.ErrTelegramFileTooLarge
+	}
 	if util.ExceedsMaxDuration(fmt.Duration) {
 		return util.ErrDurationTooLong
 	}
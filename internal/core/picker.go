@@ -0,0 +1,219 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/google/uuid"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// maximum number of formats listed in the picker
+const maxPickerFormats = 10
+
+var pickerTasks = expirable.NewLRU[string, *models.PickerTask](0, nil, 2*time.Minute)
+
+// extracts the media and, if there is more than one
+// format to choose from, replies with a keyboard listing them.
+// returns a nil result when waiting for the user to pick a format.
+func executePicker(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	extractorCtx *models.ExtractorContext,
+	isSpoiler bool,
+) (*models.TaskResult, error) {
+	media, err := extractMedia(extractorCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	// picking formats only makes sense for a single item
+	if len(media.Items) != 1 {
+		return downloadMedia(extractorCtx, media, false)
+	}
+	indexes := pickableFormats(extractorCtx, media.Items[0])
+	if len(indexes) < 2 {
+		return downloadMedia(extractorCtx, media, false)
+	}
+
+	var userID int64
+	if ctx.EffectiveUser != nil {
+		userID = ctx.EffectiveUser.Id
+	}
+
+	taskID := uuid.NewString()[:8]
+	ok := AddPickerTask(taskID, &models.PickerTask{
+		ExtractorCtx: extractorCtx,
+		Media:        media,
+		UserID:       userID,
+		IsSpoiler:    isSpoiler,
+	})
+	if !ok {
+		return nil, fmt.Errorf("failed to add picker task to cache")
+	}
+
+	localizer := localization.New(extractorCtx.Chat.Language)
+
+	_, err = ctx.EffectiveMessage.Reply(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.FormatPickerMessage.ID,
+		}),
+		&gotgbot.SendMessageOpts{
+			ReplyMarkup: getPickerKeyboard(taskID, media.Items[0], indexes),
+		},
+	)
+	if err != nil {
+		RemovePickerTask(taskID)
+		return nil, err
+	}
+	return nil, nil
+}
+
+func HandlePickerResultTask(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	task *models.PickerTask,
+	formatID string,
+) error {
+	extractorCtx := task.ExtractorCtx
+	untrack, err := trackTask(extractorCtx)
+	if err != nil {
+		return err
+	}
+	defer untrack()
+	defer extractorCtx.FilesTracker.Cleanup()
+
+	extractorCtx.FormatID = formatID
+
+	taskID := registerTask(ctx.EffectiveChat.Id, task.UserID, extractorCtx)
+	defer unregisterTask(taskID)
+
+	// the task may have been canceled while queued
+	if err := extractorCtx.Context.Err(); err != nil {
+		return err
+	}
+
+	// the picker message shows the progress
+	progress := startProgressWithMessage(bot, extractorCtx, taskID, ctx.EffectiveMessage)
+	defer progress.stop()
+
+	taskResult, shared, err := shareTask(
+		extractorCtx.Context,
+		flightKey(extractorCtx),
+		func() (*models.TaskResult, error) {
+			taskResult, err := pickedTaskResult(extractorCtx, task.Media)
+			if err != nil {
+				return nil, err
+			}
+			messages, err := sendTaskResult(bot, ctx, extractorCtx, taskResult, task.IsSpoiler)
+			if err != nil {
+				return nil, err
+			}
+			return sentTaskResult(taskResult, messages), nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if shared {
+		_, err = sendTaskResult(bot, ctx, extractorCtx, taskResult, task.IsSpoiler)
+		if err != nil {
+			return err
+		}
+	}
+
+	// picker message is no longer needed
+	ctx.EffectiveMessage.Delete(bot, nil)
+
+	return nil
+}
+
+// returns the stored media when it was stored with
+// the chosen format, downloading it otherwise
+func pickedTaskResult(
+	extractorCtx *models.ExtractorContext,
+	media *models.Media,
+) (*models.TaskResult, error) {
+	if config.Env.Caching {
+		taskResult, err := taskFromDatabase(extractorCtx)
+		if err == nil {
+			extractorCtx.Debugf("media found in database")
+			return taskResult, nil
+		}
+	}
+	return downloadMedia(extractorCtx, media, false)
+}
+
+// returns the indexes of the item formats that can
+// be picked, sorted from the best video to the best audio.
+func pickableFormats(ctx *models.ExtractorContext, item *models.MediaItem) []int {
+	indexes := make([]int, 0, len(item.Formats))
+	for i, format := range item.Formats {
+		if validateFormat(ctx, format) != nil {
+			continue
+		}
+		indexes = append(indexes, i)
+	}
+	slices.SortStableFunc(indexes, func(i, j int) int {
+		a, b := item.Formats[i], item.Formats[j]
+		if (a.VideoCodec != "") != (b.VideoCodec != "") {
+			if a.VideoCodec != "" {
+				return -1
+			}
+			return 1
+		}
+		if sa, sb := a.Width*a.Height, b.Width*b.Height; sa != sb {
+			if sa > sb {
+				return -1
+			}
+			return 1
+		}
+		if a.Bitrate > b.Bitrate {
+			return -1
+		} else if a.Bitrate < b.Bitrate {
+			return 1
+		}
+		return 0
+	})
+	if len(indexes) > maxPickerFormats {
+		indexes = indexes[:maxPickerFormats]
+	}
+	return indexes
+}
+
+func getPickerKeyboard(
+	taskID string,
+	item *models.MediaItem,
+	indexes []int,
+) gotgbot.InlineKeyboardMarkup {
+	buttons := make([]gotgbot.InlineKeyboardButton, 0, len(indexes))
+	for _, i := range indexes {
+		buttons = append(buttons, gotgbot.InlineKeyboardButton{
+			Text:         item.Formats[i].GetLabel(),
+			CallbackData: "picker." + taskID + "." + strconv.Itoa(i),
+		})
+	}
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: slices.Collect(slices.Chunk(buttons, 2)),
+	}
+}
+
+func AddPickerTask(taskID string, task *models.PickerTask) bool {
+	return !pickerTasks.Add(taskID, task)
+}
+
+func GetPickerTask(taskID string) (*models.PickerTask, bool) {
+	return pickerTasks.Get(taskID)
+}
+
+func RemovePickerTask(taskID string) bool {
+	return pickerTasks.Remove(taskID)
+}
@@ -0,0 +1,256 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const (
+	// fast tasks complete without any status message
+	progressDelay = 5 * time.Second
+	// telegram rate limits message edits
+	progressEditInterval = 3 * time.Second
+)
+
+var progressPhaseMessages = map[models.ProgressPhase]*i18n.Message{
+	models.ProgressPhaseExtracting:  localization.ProgressExtractingMessage,
+	models.ProgressPhaseDownloading: localization.ProgressDownloadingMessage,
+	models.ProgressPhaseMerging:     localization.ProgressMergingMessage,
+	models.ProgressPhaseProcessing:  localization.ProgressProcessingMessage,
+	models.ProgressPhaseUploading:   localization.ProgressUploadingMessage,
+}
+
+// keeps a status message updated with
+// the progress of a task, editing it periodically
+type progressTracker struct {
+	bot          *gotgbot.Bot
+	extractorCtx *models.ExtractorContext
+	localizer    *localization.Localizer
+	taskID       string
+
+	chatID  int64
+	replyTo int64
+	message *gotgbot.Message
+	// whether the message was sent by the tracker
+	ownsMessage bool
+
+	mu         sync.Mutex
+	phase      models.ProgressPhase
+	current    int64
+	total      int64
+	phaseStart time.Time
+	lastText   string
+
+	startedAt time.Time
+	done      chan struct{}
+	finished  chan struct{}
+}
+
+// starts tracking the progress of the task. the status message
+// is sent in reply to the given message after a short delay,
+// with a button to cancel the task.
+func startProgress(
+	bot *gotgbot.Bot,
+	extractorCtx *models.ExtractorContext,
+	taskID string,
+	replyTo *gotgbot.Message,
+) *progressTracker {
+	if replyTo == nil {
+		return nil
+	}
+	t := newProgressTracker(bot, extractorCtx, taskID)
+	t.chatID = replyTo.Chat.Id
+	t.replyTo = replyTo.MessageId
+	t.ownsMessage = true
+	go t.run()
+	return t
+}
+
+// same as startProgress, but edits
+// the given message instead of sending a new one
+func startProgressWithMessage(
+	bot *gotgbot.Bot,
+	extractorCtx *models.ExtractorContext,
+	taskID string,
+	message *gotgbot.Message,
+) *progressTracker {
+	if message == nil {
+		return nil
+	}
+	t := newProgressTracker(bot, extractorCtx, taskID)
+	t.chatID = message.Chat.Id
+	t.message = message
+	go t.run()
+	return t
+}
+
+func newProgressTracker(
+	bot *gotgbot.Bot,
+	extractorCtx *models.ExtractorContext,
+	taskID string,
+) *progressTracker {
+	t := &progressTracker{
+		bot:          bot,
+		extractorCtx: extractorCtx,
+		localizer:    localization.New(extractorCtx.Chat.Language),
+		taskID:       taskID,
+		startedAt:    time.Now(),
+		done:         make(chan struct{}),
+		finished:     make(chan struct{}),
+	}
+	extractorCtx.ProgressFunc = t.update
+	return t
+}
+
+func (t *progressTracker) update(phase models.ProgressPhase, current int64, total int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if phase != t.phase {
+		t.phase = phase
+		t.phaseStart = time.Now()
+	}
+	t.current = current
+	t.total = total
+}
+
+func (t *progressTracker) run() {
+	defer close(t.finished)
+
+	ticker := time.NewTicker(progressEditInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+			t.refresh()
+		}
+	}
+}
+
+func (t *progressTracker) refresh() {
+	if t.ownsMessage && time.Since(t.startedAt) < progressDelay {
+		return
+	}
+
+	t.mu.Lock()
+	if t.phase == "" {
+		t.mu.Unlock()
+		return
+	}
+	text := t.render()
+	if text == t.lastText {
+		t.mu.Unlock()
+		return
+	}
+	t.lastText = text
+	t.mu.Unlock()
+
+	if t.message == nil {
+		message, err := t.bot.SendMessage(
+			t.chatID, text,
+			&gotgbot.SendMessageOpts{
+				ReplyParameters: &gotgbot.ReplyParameters{
+					MessageId:                t.replyTo,
+					AllowSendingWithoutReply: true,
+				},
+				DisableNotification: true,
+				ReplyMarkup:         t.keyboard(),
+			},
+		)
+		if err != nil {
+			t.extractorCtx.Debugf("failed to send progress message: %v", err)
+			return
+		}
+		t.message = message
+		return
+	}
+	_, _, err := t.message.EditText(
+		t.bot, text,
+		&gotgbot.EditMessageTextOpts{
+			ReplyMarkup: t.keyboard(),
+		},
+	)
+	if err != nil {
+		t.extractorCtx.Debugf("failed to edit progress message: %v", err)
+	}
+}
+
+func (t *progressTracker) keyboard() gotgbot.InlineKeyboardMarkup {
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{{
+			Text: t.localizer.T(&i18n.LocalizeConfig{
+				MessageID: localization.CancelButton.ID,
+			}),
+			CallbackData: "cancel." + t.taskID,
+		}}},
+	}
+}
+
+// returns the status text, e.g.
+// "downloading...\n45% · 12.3MB/27.0MB · 2.1MB/s · eta 7s".
+// must be called with the lock held.
+func (t *progressTracker) render() string {
+	text := t.localizer.T(&i18n.LocalizeConfig{
+		MessageID: progressPhaseMessages[t.phase].ID,
+	})
+
+	var parts []string
+	if t.total > 0 {
+		percent := min(t.current*100/t.total, 100)
+		parts = append(parts, fmt.Sprintf("%d%%", percent))
+	}
+
+	elapsed := time.Since(t.phaseStart).Seconds()
+	if t.phase.IsTransfer() && t.current > 0 {
+		size := models.FormatFileSize(t.current)
+		if t.total > 0 {
+			size += "/" + models.FormatFileSize(t.total)
+		}
+		parts = append(parts, size)
+		if elapsed >= 1 {
+			speed := int64(float64(t.current) / elapsed)
+			parts = append(parts, models.FormatFileSize(speed)+"/s")
+		}
+	}
+
+	if t.total > 0 && t.current > 0 && t.current < t.total && elapsed >= 1 {
+		remaining := elapsed * float64(t.total-t.current) / float64(t.current)
+		eta := time.Duration(remaining * float64(time.Second)).Round(time.Second)
+		parts = append(parts, t.localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.ProgressETAMessage.ID,
+			TemplateData: map[string]string{
+				"ETA": eta.String(),
+			},
+		}))
+	}
+
+	if len(parts) > 0 {
+		text += "\n" + strings.Join(parts, " · ")
+	}
+	return text
+}
+
+// stops tracking the progress, deleting
+// the status message if it was sent by the tracker
+func (t *progressTracker) stop() {
+	if t == nil {
+		return
+	}
+	close(t.done)
+	<-t.finished
+
+	t.extractorCtx.ProgressFunc = nil
+	if t.ownsMessage && t.message != nil {
+		t.message.Delete(t.bot, nil)
+	}
+}
@@ -1,26 +1,143 @@
 package core
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"sync"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
 )
 
-var queueLock sync.Map
+// bounds the number of items downloaded at the same time,
+// across all tasks. created on first use, after the config is loaded.
+var downloadSlots = sync.OnceValue(func() chan struct{} {
+	return make(chan struct{}, max(config.Env.MaxConcurrentDownloads, 1))
+})
 
-func acquireQueue(key string) {
-	value, _ := queueLock.LoadOrStore(key, &sync.Mutex{})
-	mu, ok := value.(*sync.Mutex)
-	if !ok {
-		return
+func acquireDownloadSlot(ctx context.Context) error {
+	select {
+	case downloadSlots() <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	mu.Lock()
 }
 
-func releaseQueue(key string) {
-	if value, ok := queueLock.Load(key); ok {
-		mu, ok := value.(*sync.Mutex)
+func releaseDownloadSlot() {
+	<-downloadSlots()
+}
+
+// a task running for a content, whose
+// result is shared with the requests
+// for the same content made meanwhile
+type flight struct {
+	done   chan struct{}
+	result *models.TaskResult
+	err    error
+}
+
+var (
+	flightsMu sync.Mutex
+	flights   = make(map[string]*flight)
+)
+
+// runs the task once for the concurrent requests with the same
+// key: the first one runs it, the others wait for its result,
+// which is returned with shared set. the result of a task must
+// reference the sent files by id, as the downloaded ones are
+// removed when the task ends. when the task fails, possibly
+// because of the chat it was sent to, the waiting requests
+// run it again on their own.
+func shareTask(
+	ctx context.Context,
+	key string,
+	run func() (*models.TaskResult, error),
+) (*models.TaskResult, bool, error) {
+	for {
+		flightsMu.Lock()
+		f, ok := flights[key]
 		if !ok {
-			return
+			f = &flight{done: make(chan struct{})}
+			flights[key] = f
+		}
+		flightsMu.Unlock()
+
+		if !ok {
+			runFlight(key, f, run)
+			return f.result, false, f.err
+		}
+
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if f.err == nil && f.result != nil {
+			return f.result, true, nil
+		}
+	}
+}
+
+// runs the task of the flight and releases its waiting requests,
+// even when the task panics, in which case the panic is recorded
+// as the error of the flight and passed on to the caller
+func runFlight(key string, f *flight, run func() (*models.TaskResult, error)) {
+	defer func() {
+		r := recover()
+		if r != nil {
+			f.result, f.err = nil, fmt.Errorf("task panicked: %v", r)
+		}
+
+		flightsMu.Lock()
+		delete(flights, key)
+		flightsMu.Unlock()
+		close(f.done)
+
+		if r != nil {
+			panic(r)
+		}
+	}()
+	f.result, f.err = run()
+}
+
+// returns the key of the request for shareTask: requests for
+// the same content only share the result when they would
+// select the same formats
+func flightKey(ctx *models.ExtractorContext) string {
+	key := mediaCacheKey(ctx)
+	if ctx.FormatID != "" {
+		key += "#format=" + ctx.FormatID
+	}
+	if ctx.Chat != nil && ctx.Chat.VideoQuality > 0 {
+		key += "#quality=" + strconv.Itoa(int(ctx.Chat.VideoQuality))
+	}
+	return key
+}
+
+// returns the result of a sent task, referencing
+// the formats by the file ids of the sent messages
+func sentTaskResult(
+	result *models.TaskResult,
+	messages []gotgbot.Message,
+) *models.TaskResult {
+	formats := make([]*models.DownloadedFormat, 0, len(result.Formats))
+	for i, f := range result.Formats {
+		format := *f.Format
+		if i < len(messages) {
+			format.FileID = util.GetMessageFileID(&messages[i])
 		}
-		mu.Unlock()
+		formats = append(formats, &models.DownloadedFormat{
+			Format: &format,
+			Index:  f.Index,
+		})
+	}
+	return &models.TaskResult{
+		Media:    result.Media,
+		Formats:  formats,
+		IsStored: true,
 	}
 }
@@ -22,6 +22,7 @@ func SendFormats(
 ) ([]gotgbot.Message, error) {
 	var chatID int64
 	var messageOptions *gotgbot.SendMediaGroupOpts
+	var originalMessage *gotgbot.Message
 
 	chat := extractorCtx.Chat
 
@@ -37,14 +38,14 @@ func SendFormats(
 	switch {
 	case ctx.Message != nil:
 		chatID = ctx.EffectiveMessage.Chat.Id
-		messageOptions = &gotgbot.SendMediaGroupOpts{
-			ReplyParameters: &gotgbot.ReplyParameters{
-				MessageId:                ctx.EffectiveMessage.MessageId,
-				AllowSendingWithoutReply: true,
-			},
-		}
+		originalMessage = ctx.EffectiveMessage
 	case ctx.CallbackQuery != nil:
 		chatID = ctx.CallbackQuery.Message.GetChat().Id
+		// callback message (e.g. format picker)
+		// replies to the message with the link
+		if ctx.EffectiveMessage != nil {
+			originalMessage = ctx.EffectiveMessage.ReplyToMessage
+		}
 	case ctx.InlineQuery != nil:
 		chatID = ctx.InlineQuery.From.Id
 	case ctx.ChosenInlineResult != nil:
@@ -56,6 +57,23 @@ func SendFormats(
 		return nil, fmt.Errorf("failed to get chat id")
 	}
 
+	if originalMessage != nil {
+		messageOptions = &gotgbot.SendMediaGroupOpts{
+			ReplyParameters: &gotgbot.ReplyParameters{
+				MessageId:                originalMessage.MessageId,
+				AllowSendingWithoutReply: true,
+			},
+		}
+	}
+
+	caption, overflow := buildCaption(
+		options.Caption,
+		chat.Language,
+		formats,
+	)
+
+	extractorCtx.ReportProgress(models.ProgressPhaseUploading, 0, 0)
+
 	var sentMessages []gotgbot.Message
 
 	mediaGroupChunks := slices.Collect(slices.Chunk(formats, 10))
@@ -63,13 +81,13 @@ func SendFormats(
 	for _, chunk := range mediaGroupChunks {
 		var inputMediaList []gotgbot.InputMedia
 		for i, f := range chunk {
-			var caption string
+			var mediaCaption string
 			if i == 0 {
-				caption = options.Caption
+				mediaCaption = caption
 			}
 			inputMedia, err := f.Format.GetInputMedia(
 				f.FilePath, f.ThumbnailFilePath,
-				caption, options.IsSpoiler,
+				mediaCaption, options.IsSpoiler,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get input media: %w", err)
@@ -85,6 +103,9 @@ func SendFormats(
 			messageOptions,
 		)
 		if err != nil {
+			if len(sentMessages) > 0 {
+				err = fmt.Errorf("%w: %w", ErrPartiallySent, err)
+			}
 			return nil, fmt.Errorf("failed to send media group: %w", err)
 		}
 
@@ -103,10 +124,15 @@ func SendFormats(
 		return nil, fmt.Errorf("no messages sent")
 	}
 
-	if extractorCtx.Chat.DeleteLinks && ctx.Message != nil {
+	// inline results can't be replied to
+	if overflow != "" && config.Env.CaptionsOverflow && !options.Delete {
+		sendOverflow(bot, chatID, &sentMessages[0], overflow)
+	}
+
+	if extractorCtx.Chat.DeleteLinks && originalMessage != nil {
 		go func(m *gotgbot.Message) {
 			m.Delete(bot, nil)
-		}(ctx.EffectiveMessage)
+		}(originalMessage)
 	}
 
 	if !options.IsStored && config.Env.Caching {
@@ -114,10 +140,14 @@ func SendFormats(
 			extractorCtx.Context,
 			extractorCtx.Extractor,
 			media, sentMessages,
-			formats,
+			formats, extractorCtx.AudioOnly,
+			selectedFor(extractorCtx),
 		)
+		// the media was sent anyway, so the task succeeded
 		if err != nil {
-			return nil, fmt.Errorf("failed to cache formats: %w", err)
+			extractorCtx.Errorf("failed to cache formats: %v", err)
+		} else {
+			invalidateMedia(extractorCtx)
 		}
 	}
 	return sentMessages, nil
@@ -130,7 +160,7 @@ func SendInlineFormats(
 	media *models.Media,
 	formats []*models.DownloadedFormat,
 	options *models.SendFormatsOptions,
-) error {
+) ([]gotgbot.Message, error) {
 	messages, err := SendFormats(
 		bot, ctx, extractorCtx,
 		media, formats,
@@ -141,7 +171,7 @@ func SendInlineFormats(
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	msg := messages[0]
@@ -149,12 +179,18 @@ func SendInlineFormats(
 	fileID := util.GetMessageFileID(&msg)
 	format.Format.FileID = fileID
 
+	caption, _ := buildCaption(
+		options.Caption,
+		extractorCtx.Chat.Language,
+		formats,
+	)
+
 	inputMedia, err := format.Format.GetInputMedia(
 		format.FilePath, format.ThumbnailFilePath,
-		options.Caption, options.IsSpoiler,
+		caption, options.IsSpoiler,
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	_, _, err = bot.EditMessageMedia(
@@ -164,8 +200,37 @@ func SendInlineFormats(
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return messages, nil
+}
+
+// sends the part of the caption that didn't fit
+// as replies to the media, split in messages
+// within the telegram limit
+func sendOverflow(
+	bot *gotgbot.Bot,
+	chatID int64,
+	message *gotgbot.Message,
+	text string,
+) {
+	replyTo := message.MessageId
+	for text != "" {
+		var part string
+		part, text = util.TruncateHTML(text, util.TelegramMaxMessageLength)
+		msg, err := bot.SendMessage(chatID, part, &gotgbot.SendMessageOpts{
+			ReplyParameters: &gotgbot.ReplyParameters{
+				MessageId:                replyTo,
+				AllowSendingWithoutReply: true,
+			},
+			LinkPreviewOptions: &gotgbot.LinkPreviewOptions{
+				IsDisabled: true,
+			},
+		})
+		if err != nil {
+			return
+		}
+		replyTo = msg.MessageId
+	}
 }
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/models"
+)
+
+// canceled tasks stop at their next check, uploads
+// can't be interrupted. this is how long they are
+// waited for before removing their files anyway.
+const canceledTasksTimeout = 5 * time.Second
+
+var ErrShuttingDown = errors.New("shutting down")
+
+// the running tasks, waited for when shutting down
+var (
+	runningTasks   = make(map[*models.ExtractorContext]struct{})
+	runningTasksMu sync.Mutex
+	runningTasksWg sync.WaitGroup
+	shuttingDown   bool
+)
+
+// tracks the task until the returned function is called.
+// no new tasks are accepted once shutting down.
+func trackTask(extractorCtx *models.ExtractorContext) (func(), error) {
+	runningTasksMu.Lock()
+	defer runningTasksMu.Unlock()
+
+	if shuttingDown {
+		return nil, ErrShuttingDown
+	}
+	runningTasks[extractorCtx] = struct{}{}
+	runningTasksWg.Add(1)
+
+	return func() {
+		runningTasksMu.Lock()
+		delete(runningTasks, extractorCtx)
+		runningTasksMu.Unlock()
+		runningTasksWg.Done()
+	}, nil
+}
+
+func IsShuttingDown() bool {
+	runningTasksMu.Lock()
+	defer runningTasksMu.Unlock()
+	return shuttingDown
+}
+
+// stops accepting new tasks and waits for the running ones
+// until the context is done. the tasks still running are
+// then canceled, and their temporary files removed.
+func Shutdown(ctx context.Context) {
+	runningTasksMu.Lock()
+	shuttingDown = true
+	runningTasksMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		runningTasksWg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	runningTasksMu.Lock()
+	logger.L.Warnf("canceling %d running tasks", len(runningTasks))
+	for extractorCtx := range runningTasks {
+		extractorCtx.CancelFunc()
+	}
+	runningTasksMu.Unlock()
+
+	select {
+	case <-done:
+		return
+	case <-time.After(canceledTasksTimeout):
+	}
+
+	runningTasksMu.Lock()
+	defer runningTasksMu.Unlock()
+	for extractorCtx := range runningTasks {
+		extractorCtx.FilesTracker.Cleanup()
+	}
+}
@@ -2,7 +2,6 @@ package core
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
@@ -10,7 +9,6 @@ import (
 	"github.com/govdbot/govd/internal/logger"
 	"github.com/govdbot/govd/internal/models"
 	"github.com/govdbot/govd/internal/util"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -20,6 +18,8 @@ func StoreMedia(
 	media *models.Media,
 	messages []gotgbot.Message,
 	formats []*models.DownloadedFormat,
+	audioOnly bool,
+	selectedFor []int32,
 ) error {
 	if len(media.Items) == 0 {
 		return fmt.Errorf("no item to store")
@@ -49,28 +49,53 @@ func StoreMedia(
 			String: media.Caption,
 			Valid:  media.Caption != "",
 		},
-		Nsfw: media.NSFW,
+		Nsfw:         media.NSFW,
+		Title:        textOrNull(media.Title),
+		AuthorName:   textOrNull(media.AuthorName),
+		AuthorHandle: textOrNull(media.AuthorHandle),
+		UploadedAt: pgtype.Timestamptz{
+			Time:  media.UploadedAt,
+			Valid: !media.UploadedAt.IsZero(),
+		},
+		LikeCount:    countOrNull(media.LikeCount),
+		ViewCount:    countOrNull(media.ViewCount),
+		CommentCount: countOrNull(media.CommentCount),
 	})
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			// unique violation, media already exists
-			logger.L.Debugf("media %s already exists in database", media.ContentID)
-			return nil
-		}
 		return err
 	}
 
+	// the media may be stored already, with other formats
+	itemRows, err := qtx.GetMediaItems(ctx, mediaID)
+	if err != nil {
+		return err
+	}
+	if len(itemRows) > 0 && len(itemRows) != len(media.Items) {
+		logger.L.Debugf("media %s already stored with %d items", media.ContentID, len(itemRows))
+		return nil
+	}
+
 	for i := range media.Items {
-		itemID, err := qtx.CreateMediaItem(ctx, mediaID)
-		if err != nil {
-			return err
+		var itemID int64
+		if len(itemRows) > 0 {
+			itemID = itemRows[i].ID
+		} else {
+			itemID, err = qtx.CreateMediaItem(ctx, mediaID)
+			if err != nil {
+				return err
+			}
 		}
 
 		fileID := fileIDs[i]
 		fileSize := fileSizes[i]
 		format := formats[i].Format
 
+		// only the selection of videos depends on the quality limit
+		formatSelectedFor := []int32{}
+		if format.Type == database.MediaTypeVideo {
+			formatSelectedFor = selectedFor
+		}
+
 		err = qtx.CreateMediaFormat(ctx, database.CreateMediaFormatParams{
 			ItemID:   itemID,
 			FormatID: format.FormatID,
@@ -112,6 +137,8 @@ func StoreMedia(
 				Int64: format.Bitrate,
 				Valid: format.Bitrate != 0,
 			},
+			AudioOnly:   audioOnly,
+			SelectedFor: formatSelectedFor,
 		})
 		if err != nil {
 			return err
@@ -136,28 +163,40 @@ func collectMessageData(messages []gotgbot.Message) ([]string, []int64) {
 	return fileIDs, fileSizes
 }
 
+// returns the stored media with the formats usable for the
+// request: audio extracted from videos is only used in audio
+// mode, where the other formats of videos are ignored
 func ParseStoredMedia(
 	ctx context.Context,
 	extractor *models.Extractor,
 	mediaRow *database.GetMediaByContentIDRow,
+	audioOnly bool,
 ) (*models.Media, error) {
-	itemRows, err := database.Q().GetMediaItems(ctx, mediaRow.ID)
+	formatRows, err := database.Q().GetMediaFormats(ctx, mediaRow.ID)
 	if err != nil {
 		return nil, err
 	}
-	if len(itemRows) == 0 {
+	if len(formatRows) == 0 {
 		return nil, fmt.Errorf("no media items found")
 	}
 
-	items := make([]*models.MediaItem, 0, len(itemRows))
-	for _, row := range itemRows {
-		format, err := database.Q().GetMediaFormat(ctx, row.ID)
-		if err != nil {
-			return nil, err
+	// rows are sorted by item
+	var items []*models.MediaItem
+	var itemID int64
+	for _, row := range formatRows {
+		if len(items) == 0 || row.ItemID != itemID {
+			items = append(items, &models.MediaItem{})
+			itemID = row.ItemID
 		}
-		items = append(items, &models.MediaItem{
-			Formats: []*models.MediaFormat{parseFormatFromDB(&format)},
-		})
+		if audioOnly {
+			if !row.AudioOnly && row.Type == database.MediaTypeVideo {
+				continue
+			}
+		} else if row.AudioOnly {
+			continue
+		}
+		item := items[len(items)-1]
+		item.Formats = append(item.Formats, parseFormatFromDB(&row))
 	}
 
 	media := &models.Media{
@@ -167,7 +206,23 @@ func ParseStoredMedia(
 		NSFW:        mediaRow.Nsfw,
 		ExtractorID: extractor.ID,
 		Items:       items,
+
+		Title:        mediaRow.Title.String,
+		AuthorName:   mediaRow.AuthorName.String,
+		AuthorHandle: mediaRow.AuthorHandle.String,
+		UploadedAt:   mediaRow.UploadedAt.Time,
+		LikeCount:    mediaRow.LikeCount.Int64,
+		ViewCount:    mediaRow.ViewCount.Int64,
+		CommentCount: mediaRow.CommentCount.Int64,
 	}
 
 	return media, nil
 }
+
+func textOrNull(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func countOrNull(n int64) pgtype.Int8 {
+	return pgtype.Int8{Int64: n, Valid: n > 0}
+}
@@ -3,21 +3,36 @@ package core
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/govdbot/govd/internal/config"
 	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/localization"
 	"github.com/govdbot/govd/internal/models"
 	"github.com/govdbot/govd/internal/plugins"
 	"github.com/govdbot/govd/internal/util"
 	"github.com/govdbot/govd/internal/util/download"
 	"github.com/govdbot/govd/internal/util/libav"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
 )
 
-var ErrNoMedia = errors.New("no media found")
+var (
+	ErrNoMedia = errors.New("no media found")
+	// part of the media was already sent, so retrying
+	// the task would send it again
+	ErrPartiallySent = errors.New("media partially sent")
 
-func parseFormatFromDB(row *database.GetMediaFormatRow) *models.MediaFormat {
+	errNoStoredFormat = errors.New("no stored format matches the request")
+)
+
+const (
+	defaultTranscodeMaxDuration = 10 * time.Minute
+	defaultTranscodeMaxSize     = 200 * 1024 * 1024 // 200MB
+)
+
+func parseFormatFromDB(row *database.GetMediaFormatsRow) *models.MediaFormat {
 	return &models.MediaFormat{
 		FormatID:   row.FormatID,
 		FileID:     row.FileID,
@@ -31,6 +46,8 @@ func parseFormatFromDB(row *database.GetMediaFormatRow) *models.MediaFormat {
 		Width:      row.Width.Int32,
 		Height:     row.Height.Int32,
 		Bitrate:    row.Bitrate.Int64,
+
+		SelectedFor: row.SelectedFor,
 	}
 }
 
@@ -70,7 +87,7 @@ func getThumbnail(
 		format.Width = bounds.W
 		format.Height = bounds.H
 	} else if format.Type == database.MediaTypeVideo {
-		return libav.ExtractVideoThumbnail(filePath, thumbnailFilePath)
+		return libav.ExtractVideoThumbnail(ctx.Context, filePath, thumbnailFilePath)
 	}
 
 	return thumbnailFilePath, nil
@@ -86,25 +103,6 @@ func insertVideoInfo(format *models.MediaFormat, filePath string) {
 	format.Height = height
 }
 
-func formatCaption(media *models.Media, username string, isEnabled bool) string {
-	caption := media.Caption
-	if len(caption) > 600 {
-		caption = caption[:600] + "..."
-	}
-	formatText := func(s string) string {
-		s = strings.ReplaceAll(s, "{{username}}", username)
-		s = strings.ReplaceAll(s, "{{url}}", media.ContentURL)
-		s = strings.ReplaceAll(s, "{{text}}", util.Unquote(caption))
-		return s
-	}
-	var description string
-	header := formatText(config.Env.CaptionsHeader)
-	if isEnabled && caption != "" {
-		description = formatText(config.Env.CaptionsDescription)
-	}
-	return header + "\n" + description
-}
-
 // utility function to merge audio into video formats with no audio
 func mergeFormats(item *models.MediaItem, format *models.DownloadedFormat) {
 	if format.Format.Type != database.MediaTypeVideo {
@@ -123,3 +121,126 @@ func mergeFormats(item *models.MediaItem, format *models.DownloadedFormat) {
 		plugins.MergeAudio,
 	)
 }
+
+func extractAudio(ctx *models.ExtractorContext, format *models.DownloadedFormat) {
+	if !ctx.AudioOnly {
+		return
+	}
+	if format.Format.Type != database.MediaTypeVideo {
+		return
+	}
+	format.Format.Plugins = append(
+		format.Format.Plugins,
+		plugins.ExtractAudio,
+	)
+}
+
+func transcodeFormat(ctx *models.ExtractorContext, format *models.DownloadedFormat) {
+	if ctx.Config == nil || !ctx.Config.Transcode {
+		return
+	}
+	if ctx.AudioOnly || format.Format.Type != database.MediaTypeVideo {
+		return
+	}
+	// only formats that would be sent as documents
+	_, fileType := format.Format.GetInfo()
+	if fileType != models.FileTypeDocument {
+		return
+	}
+	if exceedsTranscodeLimits(ctx.Config, format) {
+		ctx.Debugf("skipping transcoding: format exceeds limits")
+		return
+	}
+	format.Format.Plugins = append(
+		format.Format.Plugins,
+		plugins.Transcode,
+	)
+}
+
+func exceedsTranscodeLimits(cfg *config.ExtractorConfig, format *models.DownloadedFormat) bool {
+	maxDuration := cfg.TranscodeMaxDuration
+	if maxDuration == 0 {
+		maxDuration = defaultTranscodeMaxDuration
+	}
+	maxSize := cfg.TranscodeMaxSize * 1024 * 1024
+	if maxSize == 0 {
+		maxSize = defaultTranscodeMaxSize
+	}
+	if format.Format.Duration > int32(maxDuration.Seconds()) {
+		return true
+	}
+	fileSize := format.Format.FileSize
+	if info, err := os.Stat(format.FilePath); err == nil {
+		fileSize = info.Size()
+	}
+	return fileSize > maxSize
+}
+
+func fitFormat(ctx *models.ExtractorContext, format *models.DownloadedFormat) {
+	if ctx.Standalone || ctx.Config == nil || !ctx.Config.FitToLimit {
+		return
+	}
+	if ctx.AudioOnly || format.Format.Type != database.MediaTypeVideo {
+		return
+	}
+	format.Format.Plugins = append(
+		format.Format.Plugins,
+		plugins.FitToLimit,
+	)
+}
+
+func tagFormat(ctx *models.ExtractorContext, format *models.DownloadedFormat) {
+	// audio extracted from videos is tagged with
+	// the cover art, even without title and artist
+	if format.Format.Title == "" && format.Format.Artist == "" && !ctx.AudioOnly {
+		return
+	}
+	// the media type is checked when the plugin runs,
+	// as the audio may still have to be extracted
+	format.Format.Plugins = append(
+		format.Format.Plugins,
+		plugins.Tags,
+	)
+}
+
+// reports whether a format failing validation only because
+// of its size can be re-encoded to fit the limits after download
+func canFitToLimit(ctx *models.ExtractorContext, format *models.MediaFormat, err error) bool {
+	if ctx.Standalone || ctx.Config == nil || !ctx.Config.FitToLimit {
+		return false
+	}
+	if ctx.AudioOnly || format.Type != database.MediaTypeVideo {
+		return false
+	}
+	return errors.Is(err, util.ErrFileTooLarge) ||
+		errors.Is(err, util.ErrTelegramFileTooLarge)
+}
+
+// appends a note about the quality loss of formats re-encoded
+// to fit the size limits, cutting the caption so that both fit
+// the telegram limit. returns the part of the caption that didn't fit.
+func buildCaption(
+	caption string,
+	language string,
+	formats []*models.DownloadedFormat,
+) (string, string) {
+	localizer := localization.New(language)
+	var note string
+	for _, f := range formats {
+		if f == nil || f.ReencodedFrom == "" {
+			continue
+		}
+		note += "\n<i>" + localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.ReencodedCaptionMessage.ID,
+			TemplateData: map[string]string{
+				"From": f.ReencodedFrom,
+				"To":   f.Format.GetLabel(),
+			},
+		}) + "</i>"
+	}
+	caption, overflow := util.TruncateHTML(
+		caption,
+		util.TelegramMaxCaptionLength-util.TelegramLength(note),
+	)
+	return caption + note, overflow
+}
@@ -7,6 +7,8 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const getOrCreateChat = `-- name: GetOrCreateChat :one
@@ -17,14 +19,14 @@ WITH upsert_chat AS (
     RETURNING chat_id, type, created_at, updated_at
 ),
 upsert_settings AS (
-    INSERT INTO settings (chat_id, language, captions, silent, nsfw, media_album_limit, delete_links)
-    VALUES ($1, $3, $4, $5, $6, $7, $8)
+    INSERT INTO settings (chat_id, language, captions, silent, nsfw, media_album_limit, delete_links, format_picker, video_quality)
+    VALUES ($1, $3, $4, $5, $6, $7, $8, $9, $10)
     ON CONFLICT (chat_id) DO UPDATE SET
         language = CASE 
             WHEN settings.language = 'XX' THEN EXCLUDED.language 
             ELSE settings.language 
         END
-    RETURNING chat_id, nsfw, media_album_limit, captions, silent, language, created_at, updated_at, disabled_extractors, delete_links
+    RETURNING chat_id, nsfw, media_album_limit, captions, silent, language, created_at, updated_at, disabled_extractors, delete_links, format_picker, video_quality, caption_header, caption_description
 ),
 final_chat AS (
     SELECT chat_id, type, created_at, updated_at FROM upsert_chat
@@ -32,7 +34,7 @@ final_chat AS (
     SELECT chat_id, type, created_at, updated_at FROM chat WHERE chat_id = $1 AND NOT EXISTS (SELECT 1 FROM upsert_chat)
 ),
 final_settings AS (
-    SELECT chat_id, nsfw, media_album_limit, captions, silent, language, created_at, updated_at, disabled_extractors, delete_links FROM upsert_settings
+    SELECT chat_id, nsfw, media_album_limit, captions, silent, language, created_at, updated_at, disabled_extractors, delete_links, format_picker, video_quality, caption_header, caption_description FROM upsert_settings
 )
 SELECT 
     c.chat_id,
@@ -43,7 +45,11 @@ SELECT
     s.silent,
     s.language,
     s.disabled_extractors,
-    s.delete_links
+    s.delete_links,
+    s.format_picker,
+    s.video_quality,
+    s.caption_header,
+    s.caption_description
 FROM final_chat c 
 JOIN final_settings s ON s.chat_id = c.chat_id
 `
@@ -57,6 +63,8 @@ type GetOrCreateChatParams struct {
 	Nsfw            bool
 	MediaAlbumLimit int32
 	DeleteLinks     bool
+	FormatPicker    bool
+	VideoQuality    int32
 }
 
 type GetOrCreateChatRow struct {
@@ -69,6 +77,10 @@ type GetOrCreateChatRow struct {
 	Language           string
 	DisabledExtractors []string
 	DeleteLinks        bool
+	FormatPicker       bool
+	VideoQuality       int32
+	CaptionHeader      pgtype.Text
+	CaptionDescription pgtype.Text
 }
 
 func (q *Queries) GetOrCreateChat(ctx context.Context, arg GetOrCreateChatParams) (GetOrCreateChatRow, error) {
@@ -81,6 +93,8 @@ func (q *Queries) GetOrCreateChat(ctx context.Context, arg GetOrCreateChatParams
 		arg.Nsfw,
 		arg.MediaAlbumLimit,
 		arg.DeleteLinks,
+		arg.FormatPicker,
+		arg.VideoQuality,
 	)
 	var i GetOrCreateChatRow
 	err := row.Scan(
@@ -93,6 +107,10 @@ func (q *Queries) GetOrCreateChat(ctx context.Context, arg GetOrCreateChatParams
 		&i.Language,
 		&i.DisabledExtractors,
 		&i.DeleteLinks,
+		&i.FormatPicker,
+		&i.VideoQuality,
+		&i.CaptionHeader,
+		&i.CaptionDescription,
 	)
 	return i, err
 }
@@ -40,6 +40,14 @@ func getDSN() string {
 	)
 }
 
+// closes the connections of the pool,
+// waiting for the queries in progress
+func Close() {
+	if pool != nil {
+		pool.Close()
+	}
+}
+
 func Q() *Queries {
 	return queries
 }
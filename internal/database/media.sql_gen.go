@@ -17,22 +17,46 @@ INSERT INTO media (
     content_url,
     extractor_id,
     caption,
-    nsfw
+    nsfw,
+    title,
+    author_name,
+    author_handle,
+    uploaded_at,
+    like_count,
+    view_count,
+    comment_count
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
-    $5
-) RETURNING id
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12
+)
+ON CONFLICT (content_id, extractor_id)
+DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+RETURNING id
 `
 
 type CreateMediaParams struct {
-	ContentID   string
-	ContentUrl  string
-	ExtractorID string
-	Caption     pgtype.Text
-	Nsfw        bool
+	ContentID    string
+	ContentUrl   string
+	ExtractorID  string
+	Caption      pgtype.Text
+	Nsfw         bool
+	Title        pgtype.Text
+	AuthorName   pgtype.Text
+	AuthorHandle pgtype.Text
+	UploadedAt   pgtype.Timestamptz
+	LikeCount    pgtype.Int8
+	ViewCount    pgtype.Int8
+	CommentCount pgtype.Int8
 }
 
 func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (int64, error) {
@@ -42,6 +66,13 @@ func (q *Queries) CreateMedia(ctx context.Context, arg CreateMediaParams) (int64
 		arg.ExtractorID,
 		arg.Caption,
 		arg.Nsfw,
+		arg.Title,
+		arg.AuthorName,
+		arg.AuthorHandle,
+		arg.UploadedAt,
+		arg.LikeCount,
+		arg.ViewCount,
+		arg.CommentCount,
 	)
 	var id int64
 	err := row.Scan(&id)
@@ -62,7 +93,9 @@ INSERT INTO media_format (
     artist,
     width,
     height,
-    bitrate
+    bitrate,
+    audio_only,
+    selected_for
 ) VALUES (
     $1,
     $2,
@@ -76,24 +109,33 @@ INSERT INTO media_format (
     $10,
     $11,
     $12,
-    $13
+    $13,
+    $14,
+    $15::int[]
 )
+ON CONFLICT (item_id, format_id, audio_only) DO UPDATE SET
+    selected_for = ARRAY(
+        SELECT DISTINCT unnest(media_format.selected_for || EXCLUDED.selected_for)
+    ),
+    updated_at = NOW()
 `
 
 type CreateMediaFormatParams struct {
-	FormatID   string
-	ItemID     int64
-	FileID     string
-	Type       MediaType
-	AudioCodec NullMediaCodec
-	VideoCodec NullMediaCodec
-	Duration   pgtype.Int4
-	FileSize   pgtype.Int8
-	Title      pgtype.Text
-	Artist     pgtype.Text
-	Width      pgtype.Int4
-	Height     pgtype.Int4
-	Bitrate    pgtype.Int8
+	FormatID    string
+	ItemID      int64
+	FileID      string
+	Type        MediaType
+	AudioCodec  NullMediaCodec
+	VideoCodec  NullMediaCodec
+	Duration    pgtype.Int4
+	FileSize    pgtype.Int8
+	Title       pgtype.Text
+	Artist      pgtype.Text
+	Width       pgtype.Int4
+	Height      pgtype.Int4
+	Bitrate     pgtype.Int8
+	AudioOnly   bool
+	SelectedFor []int32
 }
 
 func (q *Queries) CreateMediaFormat(ctx context.Context, arg CreateMediaFormatParams) error {
@@ -111,6 +153,8 @@ func (q *Queries) CreateMediaFormat(ctx context.Context, arg CreateMediaFormatPa
 		arg.Width,
 		arg.Height,
 		arg.Bitrate,
+		arg.AudioOnly,
+		arg.SelectedFor,
 	)
 	return err
 }
@@ -130,6 +174,24 @@ func (q *Queries) CreateMediaItem(ctx context.Context, mediaID int64) (int64, er
 	return id, err
 }
 
+const deleteMediaByContentID = `-- name: DeleteMediaByContentID :execrows
+DELETE FROM media WHERE content_id = $1
+AND extractor_id = $2
+`
+
+type DeleteMediaByContentIDParams struct {
+	ContentID   string
+	ExtractorID string
+}
+
+func (q *Queries) DeleteMediaByContentID(ctx context.Context, arg DeleteMediaByContentIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMediaByContentID, arg.ContentID, arg.ExtractorID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getMedia = `-- name: GetMedia :one
 SELECT 
     id,
@@ -137,17 +199,31 @@ SELECT
     content_url,
     extractor_id,
     caption,
-    nsfw
+    nsfw,
+    title,
+    author_name,
+    author_handle,
+    uploaded_at,
+    like_count,
+    view_count,
+    comment_count
 FROM media WHERE id = $1
 `
 
 type GetMediaRow struct {
-	ID          int64
-	ContentID   string
-	ContentUrl  string
-	ExtractorID string
-	Caption     pgtype.Text
-	Nsfw        bool
+	ID           int64
+	ContentID    string
+	ContentUrl   string
+	ExtractorID  string
+	Caption      pgtype.Text
+	Nsfw         bool
+	Title        pgtype.Text
+	AuthorName   pgtype.Text
+	AuthorHandle pgtype.Text
+	UploadedAt   pgtype.Timestamptz
+	LikeCount    pgtype.Int8
+	ViewCount    pgtype.Int8
+	CommentCount pgtype.Int8
 }
 
 func (q *Queries) GetMedia(ctx context.Context, id int64) (GetMediaRow, error) {
@@ -160,6 +236,13 @@ func (q *Queries) GetMedia(ctx context.Context, id int64) (GetMediaRow, error) {
 		&i.ExtractorID,
 		&i.Caption,
 		&i.Nsfw,
+		&i.Title,
+		&i.AuthorName,
+		&i.AuthorHandle,
+		&i.UploadedAt,
+		&i.LikeCount,
+		&i.ViewCount,
+		&i.CommentCount,
 	)
 	return i, err
 }
@@ -171,7 +254,14 @@ SELECT
     content_url,
     extractor_id,
     caption,
-    nsfw
+    nsfw,
+    title,
+    author_name,
+    author_handle,
+    uploaded_at,
+    like_count,
+    view_count,
+    comment_count
 FROM media WHERE content_id = $1
 AND extractor_id = $2
 `
@@ -182,12 +272,19 @@ type GetMediaByContentIDParams struct {
 }
 
 type GetMediaByContentIDRow struct {
-	ID          int64
-	ContentID   string
-	ContentUrl  string
-	ExtractorID string
-	Caption     pgtype.Text
-	Nsfw        bool
+	ID           int64
+	ContentID    string
+	ContentUrl   string
+	ExtractorID  string
+	Caption      pgtype.Text
+	Nsfw         bool
+	Title        pgtype.Text
+	AuthorName   pgtype.Text
+	AuthorHandle pgtype.Text
+	UploadedAt   pgtype.Timestamptz
+	LikeCount    pgtype.Int8
+	ViewCount    pgtype.Int8
+	CommentCount pgtype.Int8
 }
 
 func (q *Queries) GetMediaByContentID(ctx context.Context, arg GetMediaByContentIDParams) (GetMediaByContentIDRow, error) {
@@ -200,63 +297,92 @@ func (q *Queries) GetMediaByContentID(ctx context.Context, arg GetMediaByContent
 		&i.ExtractorID,
 		&i.Caption,
 		&i.Nsfw,
+		&i.Title,
+		&i.AuthorName,
+		&i.AuthorHandle,
+		&i.UploadedAt,
+		&i.LikeCount,
+		&i.ViewCount,
+		&i.CommentCount,
 	)
 	return i, err
 }
 
-const getMediaFormat = `-- name: GetMediaFormat :one
+const getMediaFormats = `-- name: GetMediaFormats :many
 SELECT 
-    format_id,
-    item_id,
-    file_id,
-    type,
-    audio_codec,
-    video_codec,
-    duration,
-    file_size,
-    title,
-    artist,
-    width,
-    height,
-    bitrate
-FROM media_format WHERE item_id = $1
+    mf.format_id,
+    mf.item_id,
+    mf.file_id,
+    mf.type,
+    mf.audio_codec,
+    mf.video_codec,
+    mf.duration,
+    mf.file_size,
+    mf.title,
+    mf.artist,
+    mf.width,
+    mf.height,
+    mf.bitrate,
+    mf.audio_only,
+    mf.selected_for
+FROM media_item mi
+JOIN media_format mf ON mf.item_id = mi.id
+WHERE mi.media_id = $1
+ORDER BY mi.id, mf.created_at
 `
 
-type GetMediaFormatRow struct {
-	FormatID   string
-	ItemID     int64
-	FileID     string
-	Type       MediaType
-	AudioCodec NullMediaCodec
-	VideoCodec NullMediaCodec
-	Duration   pgtype.Int4
-	FileSize   pgtype.Int8
-	Title      pgtype.Text
-	Artist     pgtype.Text
-	Width      pgtype.Int4
-	Height     pgtype.Int4
-	Bitrate    pgtype.Int8
+type GetMediaFormatsRow struct {
+	FormatID    string
+	ItemID      int64
+	FileID      string
+	Type        MediaType
+	AudioCodec  NullMediaCodec
+	VideoCodec  NullMediaCodec
+	Duration    pgtype.Int4
+	FileSize    pgtype.Int8
+	Title       pgtype.Text
+	Artist      pgtype.Text
+	Width       pgtype.Int4
+	Height      pgtype.Int4
+	Bitrate     pgtype.Int8
+	AudioOnly   bool
+	SelectedFor []int32
 }
 
-func (q *Queries) GetMediaFormat(ctx context.Context, itemID int64) (GetMediaFormatRow, error) {
-	row := q.db.QueryRow(ctx, getMediaFormat, itemID)
-	var i GetMediaFormatRow
-	err := row.Scan(
-		&i.FormatID,
-		&i.ItemID,
-		&i.FileID,
-		&i.Type,
-		&i.AudioCodec,
-		&i.VideoCodec,
-		&i.Duration,
-		&i.FileSize,
-		&i.Title,
-		&i.Artist,
-		&i.Width,
-		&i.Height,
-		&i.Bitrate,
-	)
-	return i, err
+func (q *Queries) GetMediaFormats(ctx context.Context, mediaID int64) ([]GetMediaFormatsRow, error) {
+	rows, err := q.db.Query(ctx, getMediaFormats, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMediaFormatsRow
+	for rows.Next() {
+		var i GetMediaFormatsRow
+		if err := rows.Scan(
+			&i.FormatID,
+			&i.ItemID,
+			&i.FileID,
+			&i.Type,
+			&i.AudioCodec,
+			&i.VideoCodec,
+			&i.Duration,
+			&i.FileSize,
+			&i.Title,
+			&i.Artist,
+			&i.Width,
+			&i.Height,
+			&i.Bitrate,
+			&i.AudioOnly,
+			&i.SelectedFor,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getMediaItems = `-- name: GetMediaItems :many
@@ -264,6 +390,7 @@ SELECT
     id,
     media_id
 FROM media_item WHERE media_id = $1
+ORDER BY id
 `
 
 type GetMediaItemsRow struct {
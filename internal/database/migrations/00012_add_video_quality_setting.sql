@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE settings ADD COLUMN video_quality INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE settings DROP COLUMN IF EXISTS video_quality;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN urls TEXT[] NOT NULL DEFAULT '{}';
+UPDATE jobs SET urls = ARRAY[url];
+ALTER TABLE jobs DROP COLUMN url;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE jobs ADD COLUMN url TEXT NOT NULL DEFAULT '';
+UPDATE jobs SET url = urls[1];
+ALTER TABLE jobs DROP COLUMN IF EXISTS urls;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE job_status ADD VALUE IF NOT EXISTS 'waiting';
+ALTER TABLE jobs ADD COLUMN collection_size INT NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD COLUMN collection_sent INT NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- enum values can't be removed, only the jobs using it
+DELETE FROM jobs WHERE status = 'waiting';
+ALTER TABLE jobs DROP COLUMN IF EXISTS collection_sent;
+ALTER TABLE jobs DROP COLUMN IF EXISTS collection_size;
+-- +goose StatementEnd
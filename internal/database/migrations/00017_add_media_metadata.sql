@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE media ADD COLUMN title TEXT;
+ALTER TABLE media ADD COLUMN author_name TEXT;
+ALTER TABLE media ADD COLUMN author_handle TEXT;
+ALTER TABLE media ADD COLUMN uploaded_at TIMESTAMPTZ;
+ALTER TABLE media ADD COLUMN like_count BIGINT;
+ALTER TABLE media ADD COLUMN view_count BIGINT;
+ALTER TABLE media ADD COLUMN comment_count BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE media DROP COLUMN IF EXISTS comment_count;
+ALTER TABLE media DROP COLUMN IF EXISTS view_count;
+ALTER TABLE media DROP COLUMN IF EXISTS like_count;
+ALTER TABLE media DROP COLUMN IF EXISTS uploaded_at;
+ALTER TABLE media DROP COLUMN IF EXISTS author_handle;
+ALTER TABLE media DROP COLUMN IF EXISTS author_name;
+ALTER TABLE media DROP COLUMN IF EXISTS title;
+-- +goose StatementEnd
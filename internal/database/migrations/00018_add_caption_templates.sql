@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE settings ADD COLUMN caption_header TEXT;
+ALTER TABLE settings ADD COLUMN caption_description TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE settings DROP COLUMN IF EXISTS caption_description;
+ALTER TABLE settings DROP COLUMN IF EXISTS caption_header;
+-- +goose StatementEnd
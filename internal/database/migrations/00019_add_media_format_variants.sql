@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE media_format ADD COLUMN audio_only BOOLEAN DEFAULT FALSE NOT NULL;
+ALTER TABLE media_format DROP CONSTRAINT IF EXISTS media_format_item_id_key;
+ALTER TABLE media_format ADD CONSTRAINT media_format_item_id_format_id_key
+    UNIQUE (item_id, format_id, audio_only);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM media_format a USING media_format b
+    WHERE a.item_id = b.item_id AND a.ctid > b.ctid;
+ALTER TABLE media_format DROP CONSTRAINT IF EXISTS media_format_item_id_format_id_key;
+ALTER TABLE media_format ADD CONSTRAINT media_format_item_id_key UNIQUE (item_id);
+ALTER TABLE media_format DROP COLUMN IF EXISTS audio_only;
+-- +goose StatementEnd
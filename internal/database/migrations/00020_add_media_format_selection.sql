@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- video quality limits (0 for none) the format was selected for
+ALTER TABLE media_format ADD COLUMN selected_for INTEGER[] DEFAULT '{}' NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE media_format DROP COLUMN IF EXISTS selected_for;
+-- +goose StatementEnd
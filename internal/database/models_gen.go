@@ -53,6 +53,50 @@ func (ns NullChatType) Value() (driver.Value, error) {
 	return string(ns.ChatType), nil
 }
 
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusFailed  JobStatus = "failed"
+	JobStatusWaiting JobStatus = "waiting"
+)
+
+func (e *JobStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = JobStatus(s)
+	case string:
+		*e = JobStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for JobStatus: %T", src)
+	}
+	return nil
+}
+
+type NullJobStatus struct {
+	JobStatus JobStatus
+	Valid     bool // Valid is true if JobStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullJobStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.JobStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.JobStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullJobStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.JobStatus), nil
+}
+
 type MediaCodec string
 
 const (
@@ -162,33 +206,60 @@ type Errors struct {
 	LastSeen    pgtype.Timestamp
 }
 
+type Jobs struct {
+	ID             int64
+	ChatID         int64
+	UserID         int64
+	AudioOnly      bool
+	Message        []byte
+	Status         JobStatus
+	Attempts       int32
+	LastError      pgtype.Text
+	RunAt          pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+	Priority       int32
+	Urls           []string
+	CollectionSize int32
+	CollectionSent int32
+}
+
 type Media struct {
-	ID          int64
-	ContentID   string
-	ContentUrl  string
-	ExtractorID string
-	Caption     pgtype.Text
-	Nsfw        bool
-	CreatedAt   pgtype.Timestamptz
-	UpdatedAt   pgtype.Timestamptz
+	ID           int64
+	ContentID    string
+	ContentUrl   string
+	ExtractorID  string
+	Caption      pgtype.Text
+	Nsfw         bool
+	CreatedAt    pgtype.Timestamptz
+	UpdatedAt    pgtype.Timestamptz
+	Title        pgtype.Text
+	AuthorName   pgtype.Text
+	AuthorHandle pgtype.Text
+	UploadedAt   pgtype.Timestamptz
+	LikeCount    pgtype.Int8
+	ViewCount    pgtype.Int8
+	CommentCount pgtype.Int8
 }
 
 type MediaFormat struct {
-	FormatID   string
-	ItemID     int64
-	FileID     string
-	Type       MediaType
-	AudioCodec NullMediaCodec
-	VideoCodec NullMediaCodec
-	Duration   pgtype.Int4
-	Title      pgtype.Text
-	Artist     pgtype.Text
-	Width      pgtype.Int4
-	Height     pgtype.Int4
-	Bitrate    pgtype.Int8
-	FileSize   pgtype.Int8
-	CreatedAt  pgtype.Timestamptz
-	UpdatedAt  pgtype.Timestamptz
+	FormatID    string
+	ItemID      int64
+	FileID      string
+	Type        MediaType
+	AudioCodec  NullMediaCodec
+	VideoCodec  NullMediaCodec
+	Duration    pgtype.Int4
+	Title       pgtype.Text
+	Artist      pgtype.Text
+	Width       pgtype.Int4
+	Height      pgtype.Int4
+	Bitrate     pgtype.Int8
+	FileSize    pgtype.Int8
+	CreatedAt   pgtype.Timestamptz
+	UpdatedAt   pgtype.Timestamptz
+	AudioOnly   bool
+	SelectedFor []int32
 }
 
 type MediaItem struct {
@@ -209,4 +280,8 @@ type Settings struct {
 	UpdatedAt          pgtype.Timestamptz
 	DisabledExtractors []string
 	DeleteLinks        bool
+	FormatPicker       bool
+	VideoQuality       int32
+	CaptionHeader      pgtype.Text
+	CaptionDescription pgtype.Text
 }
@@ -0,0 +1,85 @@
+-- name: CreateJob :one
+INSERT INTO jobs (chat_id, user_id, urls, audio_only, message, priority, status, collection_size)
+VALUES (@chat_id, @user_id, @urls, @audio_only, @message, @priority, @status, @collection_size)
+RETURNING id;
+
+-- name: GetJob :one
+SELECT * FROM jobs
+WHERE id = @id;
+
+-- name: ConfirmJob :one
+UPDATE jobs
+SET status = 'pending', run_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = @id AND status = 'waiting'
+RETURNING *;
+
+-- name: CountChatQueuedLinks :one
+SELECT COALESCE(SUM(cardinality(urls)), 0)::INT
+FROM jobs
+WHERE chat_id = @chat_id
+    AND id <> @exclude_id
+    AND status IN ('pending', 'running', 'waiting');
+
+-- name: LockJobClaims :exec
+SELECT pg_advisory_xact_lock(hashtext('jobs_claim'));
+
+-- name: ClaimJob :one
+WITH running AS (
+    SELECT chat_id, COUNT(*) AS tasks
+    FROM jobs
+    WHERE status = 'running'
+    GROUP BY chat_id
+)
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP
+WHERE id = (
+    SELECT j.id FROM jobs j
+    LEFT JOIN running r ON r.chat_id = j.chat_id
+    WHERE j.status = 'pending'
+        AND j.run_at <= CURRENT_TIMESTAMP
+        AND COALESCE(r.tasks, 0) < @max_chat_tasks::INT
+    ORDER BY j.priority DESC, COALESCE(r.tasks, 0), j.run_at, j.id
+    LIMIT 1
+    FOR UPDATE OF j SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetJobQueuePosition :one
+SELECT COUNT(*)
+FROM jobs j
+JOIN jobs target ON target.id = @id
+WHERE target.status = 'pending'
+    AND j.status = 'pending'
+    AND (j.priority > target.priority OR (j.priority = target.priority AND j.id <= target.id));
+
+-- name: DeleteJob :exec
+DELETE FROM jobs
+WHERE id = @id;
+
+-- name: DeleteWaitingJob :one
+DELETE FROM jobs
+WHERE id = @id AND status = 'waiting'
+RETURNING id;
+
+-- name: RetryJob :exec
+UPDATE jobs
+SET status = 'pending', urls = @urls, collection_sent = collection_sent + @sent::INT, run_at = @run_at, last_error = @last_error, updated_at = CURRENT_TIMESTAMP
+WHERE id = @id;
+
+-- name: FailJob :exec
+UPDATE jobs
+SET status = 'failed', last_error = @last_error, updated_at = CURRENT_TIMESTAMP
+WHERE id = @id;
+
+-- name: ResumeJobs :many
+UPDATE jobs
+SET status = 'pending', updated_at = CURRENT_TIMESTAMP
+WHERE status IN ('pending', 'running')
+RETURNING *;
+
+-- name: DeletePendingJobs :many
+DELETE FROM jobs
+WHERE status IN ('pending', 'waiting')
+    AND chat_id = @chat_id
+    AND (@user_id::BIGINT = 0 OR user_id = @user_id)
+RETURNING id;
@@ -4,14 +4,31 @@ INSERT INTO media (
     content_url,
     extractor_id,
     caption,
-    nsfw
+    nsfw,
+    title,
+    author_name,
+    author_handle,
+    uploaded_at,
+    like_count,
+    view_count,
+    comment_count
 ) VALUES (
     @content_id,
     @content_url,
     @extractor_id,
     @caption,
-    @nsfw
-) RETURNING id;
+    @nsfw,
+    @title,
+    @author_name,
+    @author_handle,
+    @uploaded_at,
+    @like_count,
+    @view_count,
+    @comment_count
+)
+ON CONFLICT (content_id, extractor_id)
+DO UPDATE SET updated_at = CURRENT_TIMESTAMP
+RETURNING id;
 
 -- name: CreateMediaItem :one
 INSERT INTO media_item (
@@ -34,7 +51,9 @@ INSERT INTO media_format (
     artist,
     width,
     height,
-    bitrate
+    bitrate,
+    audio_only,
+    selected_for
 ) VALUES (
     @format_id,
     @item_id,
@@ -48,8 +67,19 @@ INSERT INTO media_format (
     @artist,
     @width,
     @height,
-    @bitrate
-);
+    @bitrate,
+    @audio_only,
+    @selected_for::int[]
+)
+ON CONFLICT (item_id, format_id, audio_only) DO UPDATE SET
+    selected_for = ARRAY(
+        SELECT DISTINCT unnest(media_format.selected_for || EXCLUDED.selected_for)
+    ),
+    updated_at = NOW();
+
+-- name: DeleteMediaByContentID :execrows
+DELETE FROM media WHERE content_id = @content_id
+AND extractor_id = @extractor_id;
 
 -- name: GetMediaByContentID :one
 SELECT 
@@ -58,7 +88,14 @@ SELECT
     content_url,
     extractor_id,
     caption,
-    nsfw
+    nsfw,
+    title,
+    author_name,
+    author_handle,
+    uploaded_at,
+    like_count,
+    view_count,
+    comment_count
 FROM media WHERE content_id = @content_id
 AND extractor_id = @extractor_id;
 
@@ -69,28 +106,41 @@ SELECT
     content_url,
     extractor_id,
     caption,
-    nsfw
+    nsfw,
+    title,
+    author_name,
+    author_handle,
+    uploaded_at,
+    like_count,
+    view_count,
+    comment_count
 FROM media WHERE id = @id;
 
 -- name: GetMediaItems :many
 SELECT 
     id,
     media_id
-FROM media_item WHERE media_id = @media_id;
+FROM media_item WHERE media_id = @media_id
+ORDER BY id;
 
--- name: GetMediaFormat :one
+-- name: GetMediaFormats :many
 SELECT 
-    format_id,
-    item_id,
-    file_id,
-    type,
-    audio_codec,
-    video_codec,
-    duration,
-    file_size,
-    title,
-    artist,
-    width,
-    height,
-    bitrate
-FROM media_format WHERE item_id = @item_id;
\ No newline at end of file
+    mf.format_id,
+    mf.item_id,
+    mf.file_id,
+    mf.type,
+    mf.audio_codec,
+    mf.video_codec,
+    mf.duration,
+    mf.file_size,
+    mf.title,
+    mf.artist,
+    mf.width,
+    mf.height,
+    mf.bitrate,
+    mf.audio_only,
+    mf.selected_for
+FROM media_item mi
+JOIN media_format mf ON mf.item_id = mi.id
+WHERE mi.media_id = @media_id
+ORDER BY mi.id, mf.created_at;
\ No newline at end of file
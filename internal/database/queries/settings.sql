@@ -23,6 +23,11 @@ UPDATE settings
 SET media_album_limit = @media_album_limit, updated_at = CURRENT_TIMESTAMP
 WHERE chat_id = @chat_id;
 
+-- name: SetChatVideoQuality :exec
+UPDATE settings
+SET video_quality = @video_quality, updated_at = CURRENT_TIMESTAMP
+WHERE chat_id = @chat_id;
+
 -- name: AddDisabledExtractor :exec
 UPDATE settings
 SET disabled_extractors = array_append(disabled_extractors, @extractor_id), updated_at = CURRENT_TIMESTAMP
@@ -37,4 +42,13 @@ WHERE chat_id = @chat_id;
 -- name: ToggleChatDeleteLinks :exec
 UPDATE settings
 SET delete_links = NOT delete_links, updated_at = CURRENT_TIMESTAMP
+WHERE chat_id = @chat_id;
+
+-- name: ToggleChatFormatPicker :exec
+UPDATE settings
+SET format_picker = NOT format_picker, updated_at = CURRENT_TIMESTAMP
+WHERE chat_id = @chat_id;
+-- name: SetChatCaptionTemplate :exec
+UPDATE settings
+SET caption_header = @caption_header, caption_description = @caption_description, updated_at = CURRENT_TIMESTAMP
 WHERE chat_id = @chat_id;
\ No newline at end of file
@@ -28,7 +28,7 @@ downloads_stats AS (
     FROM media m
     JOIN media_item mi ON mi.media_id = m.id
     JOIN media_format mf ON mf.item_id = mi.id
-    WHERE m.created_at >= @since_date::TIMESTAMP WITH TIME ZONE
+    WHERE mf.created_at >= @since_date::TIMESTAMP WITH TIME ZONE
 )
 SELECT 
     COALESCE((SELECT SUM(total) FROM private_chats), 0)::BIGINT as total_private_chats,
@@ -7,6 +7,8 @@ package database
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const addDisabledExtractor = `-- name: AddDisabledExtractor :exec
@@ -42,6 +44,23 @@ func (q *Queries) RemoveDisabledExtractor(ctx context.Context, arg RemoveDisable
 	return err
 }
 
+const setChatCaptionTemplate = `-- name: SetChatCaptionTemplate :exec
+UPDATE settings
+SET caption_header = $1, caption_description = $2, updated_at = CURRENT_TIMESTAMP
+WHERE chat_id = $3
+`
+
+type SetChatCaptionTemplateParams struct {
+	CaptionHeader      pgtype.Text
+	CaptionDescription pgtype.Text
+	ChatID             int64
+}
+
+func (q *Queries) SetChatCaptionTemplate(ctx context.Context, arg SetChatCaptionTemplateParams) error {
+	_, err := q.db.Exec(ctx, setChatCaptionTemplate, arg.CaptionHeader, arg.CaptionDescription, arg.ChatID)
+	return err
+}
+
 const setChatLanguage = `-- name: SetChatLanguage :exec
 UPDATE settings
 SET language = $1, updated_at = CURRENT_TIMESTAMP
@@ -74,6 +93,22 @@ func (q *Queries) SetChatMediaAlbumLimit(ctx context.Context, arg SetChatMediaAl
 	return err
 }
 
+const setChatVideoQuality = `-- name: SetChatVideoQuality :exec
+UPDATE settings
+SET video_quality = $1, updated_at = CURRENT_TIMESTAMP
+WHERE chat_id = $2
+`
+
+type SetChatVideoQualityParams struct {
+	VideoQuality int32
+	ChatID       int64
+}
+
+func (q *Queries) SetChatVideoQuality(ctx context.Context, arg SetChatVideoQualityParams) error {
+	_, err := q.db.Exec(ctx, setChatVideoQuality, arg.VideoQuality, arg.ChatID)
+	return err
+}
+
 const toggleChatCaptions = `-- name: ToggleChatCaptions :exec
 UPDATE settings
 SET captions = NOT captions, updated_at = CURRENT_TIMESTAMP
@@ -96,6 +131,17 @@ func (q *Queries) ToggleChatDeleteLinks(ctx context.Context, chatID int64) error
 	return err
 }
 
+const toggleChatFormatPicker = `-- name: ToggleChatFormatPicker :exec
+UPDATE settings
+SET format_picker = NOT format_picker, updated_at = CURRENT_TIMESTAMP
+WHERE chat_id = $1
+`
+
+func (q *Queries) ToggleChatFormatPicker(ctx context.Context, chatID int64) error {
+	_, err := q.db.Exec(ctx, toggleChatFormatPicker, chatID)
+	return err
+}
+
 const toggleChatNsfw = `-- name: ToggleChatNsfw :exec
 UPDATE settings
 SET nsfw = NOT nsfw, updated_at = CURRENT_TIMESTAMP
@@ -6,6 +6,7 @@ import (
 	"maps"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/govdbot/govd/internal/database"
@@ -55,7 +56,7 @@ var StoriesExtractor = &models.Extractor{
 	ID:          "instagram",
 	DisplayName: "Instagram Stories",
 
-	URLPattern: regexp.MustCompile(`https:\/\/(www\.)?(?:dd)?instagram\.com\/stories\/[a-zA-Z0-9._]+\/(?P<id>\d+)`),
+	URLPattern: regexp.MustCompile(`https:\/\/(www\.)?(?:dd)?instagram\.com\/stories\/(?P<username>[a-zA-Z0-9._]+)\/(?P<id>\d+)`),
 	Host:       instagramHost,
 	Hidden:     true,
 
@@ -191,6 +192,10 @@ func GetIGramStory(ctx *models.ExtractorContext) (*models.Media, error) {
 	isVideo := len(result.VideoVersions) > 0
 
 	media := ctx.NewMedia()
+	media.AuthorHandle = ctx.MatchGroups["username"]
+	if result.TakenAt > 0 {
+		media.UploadedAt = time.Unix(int64(result.TakenAt), 0)
+	}
 	item := media.NewItem()
 	if isVideo {
 		video := GetBestVideoVersion(result.VideoVersions)
@@ -64,6 +64,20 @@ type Media struct {
 	Title                 string                 `json:"title"`
 	VideoURL              string                 `json:"video_url"`
 	VideoViewCount        int                    `json:"video_view_count"`
+	VideoPlayCount        int                    `json:"video_play_count"`
+	Owner                 *Owner                 `json:"owner"`
+	EdgeMediaPreviewLike  *EdgeCount             `json:"edge_media_preview_like"`
+	EdgeMediaToComment    *EdgeCount             `json:"edge_media_to_comment"`
+	EdgeMediaToParent     *EdgeCount             `json:"edge_media_to_parent_comment"`
+}
+
+type Owner struct {
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+}
+
+type EdgeCount struct {
+	Count int64 `json:"count"`
 }
 
 type Posts struct {
@@ -68,6 +68,7 @@ func ParseGQLMedia(ctx *models.ExtractorContext, data *Media) (*models.Media, er
 
 	media := ctx.NewMedia()
 	media.SetCaption(caption)
+	setGQLDetails(media, data)
 
 	switch data.Typename {
 	case "GraphVideo", "XDTGraphVideo":
@@ -124,6 +125,27 @@ func ParseGQLMedia(ctx *models.ExtractorContext, data *Media) (*models.Media, er
 	return media, nil
 }
 
+// fills the details of the media shown in the caption
+func setGQLDetails(media *models.Media, data *Media) {
+	if data.Owner != nil {
+		media.AuthorName = data.Owner.FullName
+		media.AuthorHandle = data.Owner.Username
+	}
+	if data.TakenAtTimestamp > 0 {
+		media.UploadedAt = time.Unix(int64(data.TakenAtTimestamp), 0)
+	}
+	if data.EdgeMediaPreviewLike != nil {
+		media.LikeCount = data.EdgeMediaPreviewLike.Count
+	}
+	switch {
+	case data.EdgeMediaToParent != nil:
+		media.CommentCount = data.EdgeMediaToParent.Count
+	case data.EdgeMediaToComment != nil:
+		media.CommentCount = data.EdgeMediaToComment.Count
+	}
+	media.ViewCount = int64(max(data.VideoPlayCount, data.VideoViewCount))
+}
+
 func ParseEmbedGQL(body []byte) (*Media, error) {
 	match := embedPattern.FindSubmatch(body)
 	if len(match) < 2 {
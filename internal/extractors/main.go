@@ -19,6 +19,7 @@ var Extractors = []*models.Extractor{
 	facebook.Extractor,
 	tiktok.Extractor,
 	tiktok.VMExtractor,
+	soundcloud.SetExtractor,
 	soundcloud.Extractor,
 	soundcloud.ShortExtractor,
 	twitter.Extractor,
@@ -27,6 +28,7 @@ var Extractors = []*models.Extractor{
 	instagram.StoriesExtractor,
 	instagram.ShareURLExtractor,
 	ninegag.Extractor,
+	youtube.PlaylistExtractor,
 	youtube.Extractor,
 	pinterest.ShortExtractor,
 	pinterest.Extractor,
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/logger"
@@ -84,6 +85,16 @@ func MediaFromAPI(ctx *models.ExtractorContext) (*models.Media, error) {
 	}
 	media.SetCaption(title)
 
+	media.Title = title
+	if data.Author != "[deleted]" {
+		media.AuthorHandle = data.Author
+	}
+	if data.CreatedUTC > 0 {
+		media.UploadedAt = time.Unix(int64(data.CreatedUTC), 0)
+	}
+	media.LikeCount = data.Score
+	media.CommentCount = data.NumComments
+
 	if !data.IsVideo {
 		// check for single photo
 		if data.Preview != nil && len(data.Preview.Images) > 0 {
@@ -24,6 +24,10 @@ type PostData struct {
 	MediaMetadata map[string]MediaMetadata `json:"media_metadata"`
 	SecureMedia   *Media                   `json:"secure_media"`
 	Over18        bool                     `json:"over_18"`
+	Author        string                   `json:"author"`
+	CreatedUTC    float64                  `json:"created_utc"`
+	Score         int64                    `json:"score"`
+	NumComments   int64                    `json:"num_comments"`
 }
 
 type Media struct {
@@ -5,12 +5,14 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/logger"
 	"github.com/govdbot/govd/internal/models"
-	"github.com/govdbot/govd/internal/plugins"
 	"github.com/govdbot/govd/internal/util"
 
 	"github.com/bytedance/sonic"
@@ -20,6 +22,10 @@ import (
 const (
 	apiHostname = "https://api-v2.soundcloud.com/"
 	baseURL     = "https://soundcloud.com/"
+
+	// tracks fetched per request when
+	// resolving the entries of a set
+	tracksPerRequest = 50
 )
 
 var ShortExtractor = &models.Extractor{
@@ -42,6 +48,24 @@ var ShortExtractor = &models.Extractor{
 	},
 }
 
+var SetExtractor = &models.Extractor{
+	ID:          "soundcloud",
+	DisplayName: "SoundCloud (Set)",
+
+	URLPattern: regexp.MustCompile(`(?i)^(?:https?://)?(?:www\.|m\.)?soundcloud\.com/(?P<uploader>[\w\d-]+)/sets/(?P<id>[\w\d-]+)(?:/(?P<token>[^/?#]+))?(?:[?].*)?$`),
+	Host:       []string{"soundcloud"},
+
+	GetFunc: func(ctx *models.ExtractorContext) (*models.ExtractorResponse, error) {
+		entries, err := GetSetEntries(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get set entries: %w", err)
+		}
+		return &models.ExtractorResponse{
+			Entries: entries,
+		}, nil
+	},
+}
+
 var Extractor = &models.Extractor{
 	ID:          "soundcloud",
 	DisplayName: "SoundCloud",
@@ -123,6 +147,20 @@ func GetTrackMedia(ctx *models.ExtractorContext) (*models.Media, error) {
 	}
 
 	media := ctx.NewMedia()
+	media.Title = title
+	media.AuthorName = artist
+	media.AuthorHandle = manifest.User.Permalink
+	for _, date := range []string{manifest.DisplayDate, manifest.CreatedAt} {
+		uploadedAt, err := time.Parse(time.RFC3339, date)
+		if err == nil {
+			media.UploadedAt = uploadedAt
+			break
+		}
+	}
+	media.LikeCount = manifest.LikesCount
+	media.ViewCount = manifest.PlaybackCount
+	media.CommentCount = manifest.CommentCount
+
 	item := media.NewItem()
 	item.AddFormats(&models.MediaFormat{
 		FormatID:     "mp3",
@@ -133,12 +171,137 @@ func GetTrackMedia(ctx *models.ExtractorContext) (*models.Media, error) {
 		Duration:     duration,
 		Title:        title,
 		Artist:       artist,
-		Plugins:      []*models.Plugin{plugins.ID3},
 	})
 
 	return media, nil
 }
 
+// returns the urls of the tracks of the set, in order
+func GetSetEntries(ctx *models.ExtractorContext) ([]string, error) {
+	resolveTitle := ctx.MatchGroups["uploader"] + "/sets/" + ctx.ContentID
+	token := ctx.MatchGroups["token"]
+	if token != "" {
+		resolveTitle += "/" + token
+	}
+
+	clientID, err := GetClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	playlist, err := GetPlaylist(ctx, ResolveURL(baseURL+resolveTitle), clientID)
+	if err != nil {
+		return nil, err
+	}
+	if len(playlist.Tracks) == 0 {
+		return nil, util.ErrUnavailable
+	}
+
+	// only the first tracks of the set are complete,
+	// the permalink of the others must be fetched
+	permalinks := make(map[int64]string, len(playlist.Tracks))
+	var missing []int64
+	for _, track := range playlist.Tracks {
+		if track.PermalinkURL != "" {
+			permalinks[track.ID] = track.PermalinkURL
+		} else {
+			missing = append(missing, track.ID)
+		}
+	}
+	for chunk := range slices.Chunk(missing, tracksPerRequest) {
+		tracks, err := GetTracks(ctx, chunk, clientID)
+		if err != nil {
+			return nil, err
+		}
+		for _, track := range tracks {
+			permalinks[track.ID] = track.PermalinkURL
+		}
+	}
+
+	entries := make([]string, 0, len(playlist.Tracks))
+	for _, track := range playlist.Tracks {
+		permalink := permalinks[track.ID]
+		if permalink == "" {
+			// removed or not available in the region
+			continue
+		}
+		entries = append(entries, permalink)
+	}
+
+	return entries, nil
+}
+
+func GetPlaylist(
+	ctx *models.ExtractorContext,
+	playlistURL string,
+	clientID string,
+) (*Playlist, error) {
+	reqURL := playlistURL + "&client_id=" + clientID
+
+	resp, err := ctx.Fetch(
+		http.MethodGet,
+		reqURL, nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logger.WriteFile("soundcloud_playlist_response", resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get playlist info: %s", resp.Status)
+	}
+
+	var playlist Playlist
+	decoder := sonic.ConfigFastest.NewDecoder(resp.Body)
+	err = decoder.Decode(&playlist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return &playlist, nil
+}
+
+func GetTracks(
+	ctx *models.ExtractorContext,
+	ids []int64,
+	clientID string,
+) ([]*Track, error) {
+	idList := make([]string, 0, len(ids))
+	for _, id := range ids {
+		idList = append(idList, strconv.FormatInt(id, 10))
+	}
+	queryParams := url.Values{}
+	queryParams["ids"] = []string{strings.Join(idList, ",")}
+	queryParams["client_id"] = []string{clientID}
+	reqURL := apiHostname + "tracks?" + queryParams.Encode()
+
+	resp, err := ctx.Fetch(
+		http.MethodGet,
+		reqURL, nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	logger.WriteFile("soundcloud_tracks_response", resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get tracks: %s", resp.Status)
+	}
+
+	var tracks []*Track
+	decoder := sonic.ConfigFastest.NewDecoder(resp.Body)
+	err = decoder.Decode(&tracks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return tracks, nil
+}
+
 func GetTrackManifest(
 	ctx *models.ExtractorContext,
 	trackURL string,
@@ -1,8 +1,9 @@
 package soundcloud
 
 type User struct {
-	ID       int64  `json:"id"`
-	Username string `json:"username"`
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	Permalink string `json:"permalink"`
 }
 
 type Format struct {
@@ -21,13 +22,25 @@ type Media struct {
 }
 
 type Track struct {
-	ID           int64  `json:"id"`
-	Title        string `json:"title"`
-	Description  string `json:"description"`
-	ArtworkURL   string `json:"artwork_url"`
-	User         *User  `json:"user"`
-	Media        *Media `json:"media"`
-	FullDuration int32  `json:"full_duration"`
+	ID            int64  `json:"id"`
+	Title         string `json:"title"`
+	Description   string `json:"description"`
+	ArtworkURL    string `json:"artwork_url"`
+	User          *User  `json:"user"`
+	Media         *Media `json:"media"`
+	FullDuration  int32  `json:"full_duration"`
+	PermalinkURL  string `json:"permalink_url"`
+	DisplayDate   string `json:"display_date"`
+	CreatedAt     string `json:"created_at"`
+	LikesCount    int64  `json:"likes_count"`
+	PlaybackCount int64  `json:"playback_count"`
+	CommentCount  int64  `json:"comment_count"`
+}
+
+type Playlist struct {
+	ID     int64    `json:"id"`
+	Title  string   `json:"title"`
+	Tracks []*Track `json:"tracks"`
 }
 
 type TrackManifest struct {
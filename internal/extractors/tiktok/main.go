@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"time"
 
 	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/models"
@@ -85,6 +86,19 @@ func GetMedia(ctx *models.ExtractorContext) (*models.Media, error) {
 	media := ctx.NewMedia()
 	media.SetCaption(details.Desc)
 
+	if details.Author != nil {
+		media.AuthorName = details.Author.Nickname
+		media.AuthorHandle = details.Author.UniqueID
+	}
+	if details.CreateTime > 0 {
+		media.UploadedAt = time.Unix(int64(details.CreateTime), 0)
+	}
+	if details.Stats != nil {
+		media.LikeCount = int64(details.Stats.DiggCount)
+		media.ViewCount = int64(details.Stats.PlayCount)
+		media.CommentCount = int64(details.Stats.CommentCount)
+	}
+
 	isImageSlide := details.ImagePost != nil
 	if !isImageSlide {
 		item := media.NewItem()
@@ -1,5 +1,10 @@
 package tiktok
 
+import (
+	"strconv"
+	"strings"
+)
+
 type Response struct {
 	AwemeDetails []*AwemeDetail `json:"aweme_details"`
 	StatusCode   int            `json:"status_code"`
@@ -51,10 +56,41 @@ type AwemeDetail struct {
 }
 
 type WebItemStruct struct {
-	ID        string        `json:"id"`
-	Desc      string        `json:"desc"`
-	Video     *WebVideo     `json:"video"`
-	ImagePost *WebImagePost `json:"imagePost"`
+	ID         string        `json:"id"`
+	Desc       string        `json:"desc"`
+	Video      *WebVideo     `json:"video"`
+	ImagePost  *WebImagePost `json:"imagePost"`
+	CreateTime FlexInt       `json:"createTime"`
+	Author     *WebAuthor    `json:"author"`
+	Stats      *WebStats     `json:"stats"`
+}
+
+type WebAuthor struct {
+	UniqueID string `json:"uniqueId"`
+	Nickname string `json:"nickname"`
+}
+
+type WebStats struct {
+	DiggCount    FlexInt `json:"diggCount"`
+	PlayCount    FlexInt `json:"playCount"`
+	CommentCount FlexInt `json:"commentCount"`
+}
+
+// an integer sent either as a number or as a string
+type FlexInt int64
+
+func (i *FlexInt) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		*i = 0
+		return nil
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*i = FlexInt(v)
+	return nil
 }
 
 type WebImagePost struct {
@@ -5,6 +5,8 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
+	"time"
 
 	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/models"
@@ -92,6 +94,15 @@ func MediaFromAPI(ctx *models.ExtractorContext) (*models.Media, error) {
 	caption := SanitizeCaption(tweetData.FullText)
 	media.SetCaption(caption)
 
+	if tweetData.Author != nil {
+		media.AuthorName = tweetData.Author.Name
+		media.AuthorHandle = tweetData.Author.ScreenName
+	}
+	media.UploadedAt, _ = time.Parse(time.RubyDate, tweetData.CreatedAt)
+	media.LikeCount = int64(tweetData.FavoriteCount)
+	media.ViewCount = tweetData.ViewCount
+	media.CommentCount = int64(tweetData.ReplyCount)
+
 	var mediaEntities []*MediaEntity
 	switch {
 	case tweetData.Entities != nil && len(tweetData.Entities.Media) > 0:
@@ -174,14 +185,32 @@ func GetTweetAPI(ctx *models.ExtractorContext) (*Tweet, error) {
 	}
 
 	var tweet *Tweet
+	var core *Core
+	var views *ViewsInfo
 	switch {
 	case result.Tweet != nil:
 		tweet = result.Tweet.Legacy
+		core, views = result.Tweet.Core, result.Tweet.Views
 	case result.Legacy != nil:
 		tweet = result.Legacy
+		core, views = result.Core, result.Views
 	default:
 		return nil, fmt.Errorf("tweet data not found")
 	}
+	if tweet == nil {
+		return nil, fmt.Errorf("tweet data not found")
+	}
+
+	if core != nil {
+		user := core.UserResults.Result
+		tweet.Author = user.Legacy
+		if user.Core != nil && user.Core.ScreenName != "" {
+			tweet.Author = user.Core
+		}
+	}
+	if views != nil {
+		tweet.ViewCount, _ = strconv.ParseInt(views.Count, 10, 64)
+	}
 
 	return tweet, nil
 }
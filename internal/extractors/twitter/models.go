@@ -47,6 +47,8 @@ type Core struct {
 			TypeName string      `json:"__typename"`
 			RestID   string      `json:"rest_id"`
 			Legacy   *UserLegacy `json:"legacy"`
+			// newer responses moved the names here
+			Core *UserLegacy `json:"core"`
 		} `json:"result"`
 	} `json:"user_results"`
 }
@@ -73,6 +75,11 @@ type Tweet struct {
 	ConversationID    string            `json:"conversation_id_str"`
 	Lang              string            `json:"lang"`
 	UserIDStr         string            `json:"user_id_str"`
+
+	// not part of the legacy object,
+	// filled from the tweet result
+	Author    *UserLegacy `json:"-"`
+	ViewCount int64       `json:"-"`
 }
 
 type ExtendedEntities struct {
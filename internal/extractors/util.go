@@ -2,6 +2,8 @@ package extractors
 
 import (
 	"context"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/govdbot/govd/internal/config"
@@ -13,6 +15,9 @@ import (
 
 const maxRedirects = 5
 
+// content ids are used as directory names
+var cassetteNameReplacer = strings.NewReplacer("/", "_", "\\", "_", "..", "_")
+
 var extractorsByHost = getExtractorsMap()
 
 func FromURL(url string) *models.ExtractorContext {
@@ -89,6 +94,7 @@ func FromURL(url string) *models.ExtractorContext {
 					Proxy:         cfg.Proxy,
 					DisableProxy:  cfg.DisableProxy,
 					Impersonate:   cfg.Impersonate,
+					Cassette:      newCassette(extractor, groups["id"]),
 				},
 			),
 		}
@@ -125,6 +131,23 @@ func FromURL(url string) *models.ExtractorContext {
 	return nil
 }
 
+// returns the cassette recording or replaying the http
+// requests of the content, nil when cassettes are disabled
+func newCassette(extractor *models.Extractor, contentID string) *networking.Cassette {
+	if config.Env.CassetteMode == "" {
+		return nil
+	}
+	if contentID == "" {
+		contentID = "unknown"
+	}
+	dir := filepath.Join(
+		config.Env.CassetteDir,
+		extractor.ID,
+		cassetteNameReplacer.Replace(contentID),
+	)
+	return networking.NewCassette(dir, networking.CassetteMode(config.Env.CassetteMode))
+}
+
 func getExtractorsMap() map[string][]*models.Extractor {
 	extractorsByHost := make(map[string][]*models.Extractor)
 	for _, extractor := range Extractors {
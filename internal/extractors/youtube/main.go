@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"time"
 
 	"github.com/govdbot/govd/internal/logger"
 	"github.com/govdbot/govd/internal/models"
@@ -95,6 +96,14 @@ func GetFromInstance(ctx *models.ExtractorContext, instance string) (*models.Med
 	}
 
 	media := ctx.NewMedia()
+	media.Title = data.Title
+	media.AuthorName = data.Author
+	if data.Published > 0 {
+		media.UploadedAt = time.Unix(int64(data.Published), 0)
+	}
+	media.LikeCount = int64(data.LikeCount)
+	media.ViewCount = int64(data.ViewCount)
+
 	item := media.NewItem()
 	item.AddFormats(formats...)
 
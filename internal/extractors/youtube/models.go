@@ -75,3 +75,21 @@ type FormatStream struct {
 	Container    string `json:"container"`
 	Encoding     string `json:"encoding"`
 }
+
+type InvPlaylistResponse struct {
+	Type       string              `json:"type"`
+	Title      string              `json:"title"`
+	PlaylistID string              `json:"playlistId"`
+	Author     string              `json:"author"`
+	VideoCount int                 `json:"videoCount"`
+	Videos     []*InvPlaylistVideo `json:"videos"`
+	Error      string              `json:"error,omitempty"`
+}
+
+type InvPlaylistVideo struct {
+	Title         string `json:"title"`
+	VideoID       string `json:"videoId"`
+	Author        string `json:"author"`
+	Index         int    `json:"index"`
+	LengthSeconds int32  `json:"lengthSeconds"`
+}
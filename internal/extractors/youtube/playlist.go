@@ -0,0 +1,141 @@
+package youtube
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+
+	"github.com/bytedance/sonic"
+)
+
+// titles of the playlist videos that can't be downloaded
+var unavailableVideoTitles = []string{
+	"[Private video]",
+	"[Deleted video]",
+}
+
+var PlaylistExtractor = &models.Extractor{
+	ID:          "youtube",
+	DisplayName: "YouTube (Playlist)",
+
+	URLPattern: regexp.MustCompile(`(?:https?:)?(?:\/\/)?(?:(?:www|m|music)\.)?youtube\.com\/playlist\?(?:.*&)?list=(?P<id>[\w-]+)`),
+	Host: []string{
+		"youtube",
+	},
+
+	GetFunc: func(ctx *models.ExtractorContext) (*models.ExtractorResponse, error) {
+		entries, err := GetPlaylistFromInv(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &models.ExtractorResponse{
+			Entries: entries,
+		}, nil
+	},
+}
+
+func GetPlaylistFromInv(ctx *models.ExtractorContext) ([]string, error) {
+	if ctx.Config == nil {
+		return nil, fmt.Errorf("youtube not configured")
+	}
+	err := fmt.Errorf("no youtube instance configured")
+	for i := range ctx.Config.Instance {
+		instance, instanceErr := GetInvInstance(ctx, i)
+		if instanceErr != nil {
+			continue
+		}
+		var entries []string
+		entries, err = GetPlaylistFromInstance(ctx, instance)
+		if err == nil {
+			return entries, nil
+		}
+		ctx.Debugf("invidious instance %s failed: %v", instance, err)
+	}
+	return nil, err
+}
+
+// returns the urls of the playlist videos, in order. only the
+// entries that can be queued are fetched, as the others
+// would be dropped when the collection is expanded
+func GetPlaylistFromInstance(ctx *models.ExtractorContext, instance string) ([]string, error) {
+	maxEntries := max(config.Env.MaxCollectionEntries, 1)
+
+	var entries []string
+	seen := make(map[string]bool)
+	for page := 1; len(entries) < maxEntries; page++ {
+		playlist, err := getPlaylistPage(ctx, instance, page)
+		if err != nil {
+			return nil, err
+		}
+		added := 0
+		for _, video := range playlist.Videos {
+			if video.VideoID == "" || seen[video.VideoID] {
+				continue
+			}
+			seen[video.VideoID] = true
+			added++
+			if slices.Contains(unavailableVideoTitles, video.Title) {
+				continue
+			}
+			entries = append(entries, videoURL+video.VideoID)
+		}
+		// pages past the end are empty
+		// or repeat the last videos
+		if added == 0 || len(seen) >= playlist.VideoCount {
+			break
+		}
+	}
+	if len(entries) == 0 {
+		return nil, util.ErrUnavailable
+	}
+	return entries[:min(len(entries), maxEntries)], nil
+}
+
+func getPlaylistPage(
+	ctx *models.ExtractorContext,
+	instance string,
+	page int,
+) (*InvPlaylistResponse, error) {
+	reqURL := instance +
+		invPlaylistEndpoint +
+		ctx.ContentID +
+		"?page=" + strconv.Itoa(page)
+
+	ctx.Debugf("invidious playlist api: %s", reqURL)
+
+	resp, err := ctx.Fetch(
+		http.MethodGet,
+		reqURL, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	logger.WriteFile("inv_youtube_playlist_response", resp)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, util.ErrUnavailable
+	}
+
+	var data *InvPlaylistResponse
+	decoder := sonic.ConfigFastest.NewDecoder(resp.Body)
+	err = decoder.Decode(&data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if data.Error != "" {
+			return nil, fmt.Errorf("bad response: %s: %s", resp.Status, data.Error)
+		}
+		return nil, fmt.Errorf("bad response: %s", resp.Status)
+	}
+	return data, nil
+}
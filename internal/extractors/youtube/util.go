@@ -11,7 +11,12 @@ import (
 	"github.com/govdbot/govd/internal/util"
 )
 
-const invEndpoint = "/api/v1/videos/"
+const (
+	invEndpoint         = "/api/v1/videos/"
+	invPlaylistEndpoint = "/api/v1/playlists/"
+
+	videoURL = "https://www.youtube.com/watch?v="
+)
 
 func ParseInvFormats(data *InvResponse, instance string) []*models.MediaFormat {
 	formats := make([]*models.MediaFormat, 0, len(data.AdaptiveFormats))
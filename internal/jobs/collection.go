@@ -0,0 +1,178 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// ErrJobNotWaiting is returned when confirming a collection
+// that was already confirmed, canceled or does not exist.
+var ErrJobNotWaiting = errors.New("job is not waiting for confirmation")
+
+// queues the entries of a collection found by the job as
+// a new job, downloading them in order. collections with
+// many entries wait for the user to confirm them first.
+func expandCollection(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	chat *database.GetOrCreateChatRow,
+	job *database.Jobs,
+	entries []string,
+) error {
+	// the limit is shared by every collection of the
+	// chat, so a single user can't flood the queue
+	queued, err := database.Q().CountChatQueuedLinks(
+		context.Background(),
+		database.CountChatQueuedLinksParams{
+			ChatID:    job.ChatID,
+			ExcludeID: job.ID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	available := config.Env.MaxCollectionEntries - int(queued)
+	if available <= 0 {
+		return util.ErrCollectionLimitExceeded
+	}
+	if len(entries) > available {
+		logger.L.Debugf(
+			"job %d: keeping %d of %d collection entries",
+			job.ID, available, len(entries),
+		)
+		entries = entries[:available]
+	}
+
+	status := database.JobStatusPending
+	needsConfirm := len(entries) > config.Env.CollectionConfirmEntries
+	if needsConfirm {
+		status = database.JobStatusWaiting
+	}
+
+	jobID, err := database.Q().CreateJob(
+		context.Background(),
+		database.CreateJobParams{
+			ChatID:         job.ChatID,
+			UserID:         job.UserID,
+			Urls:           entries,
+			AudioOnly:      job.AudioOnly,
+			Message:        job.Message,
+			Priority:       job.Priority,
+			Status:         status,
+			CollectionSize: int32(len(entries)),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	localizer := localization.New(chat.Language)
+
+	if needsConfirm {
+		ctx.EffectiveMessage.Reply(
+			bot, localizer.T(&i18n.LocalizeConfig{
+				MessageID: localization.CollectionConfirmMessage.ID,
+				TemplateData: map[string]int{
+					"Count": len(entries),
+				},
+			}),
+			&gotgbot.SendMessageOpts{
+				ReplyMarkup: collectionKeyboard(localizer, jobID),
+			},
+		)
+		return nil
+	}
+
+	wakeWorker()
+	go notifyQueuePosition(bot, ctx.EffectiveMessage, jobID)
+
+	return nil
+}
+
+func collectionKeyboard(localizer *localization.Localizer, jobID int64) gotgbot.InlineKeyboardMarkup {
+	id := strconv.FormatInt(jobID, 10)
+	return gotgbot.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gotgbot.InlineKeyboardButton{{
+			{
+				Text: localizer.T(&i18n.LocalizeConfig{
+					MessageID: localization.CollectionConfirmButton.ID,
+				}),
+				CallbackData: "collection.confirm." + id,
+			},
+			{
+				Text: localizer.T(&i18n.LocalizeConfig{
+					MessageID: localization.CancelButton.ID,
+				}),
+				CallbackData: "collection.cancel." + id,
+			},
+		}},
+	}
+}
+
+// returns the user who requested the
+// collection waiting for confirmation
+func GetCollectionUser(jobID int64) (int64, bool) {
+	job, err := database.Q().GetJob(context.Background(), jobID)
+	if err != nil || job.Status != database.JobStatusWaiting {
+		return 0, false
+	}
+	return job.UserID, true
+}
+
+// queues the collection waiting for confirmation,
+// returning the number of entries queued
+func ConfirmCollection(jobID int64) (int, error) {
+	job, err := database.Q().ConfirmJob(context.Background(), jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrJobNotWaiting
+	}
+	if err != nil {
+		return 0, err
+	}
+	wakeWorker()
+	return len(job.Urls), nil
+}
+
+// deletes the collection waiting for confirmation
+func DiscardCollection(jobID int64) error {
+	_, err := database.Q().DeleteWaitingJob(context.Background(), jobID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrJobNotWaiting
+	}
+	return err
+}
+
+// tells how many entries of the collection were sent,
+// once every entry was either sent or failed
+func sendCollectionSummary(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	chat *database.GetOrCreateChatRow,
+	sent int,
+	total int,
+) {
+	localizer := localization.New(chat.Language)
+	ctx.EffectiveMessage.Reply(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.CollectionSummaryMessage.ID,
+			TemplateData: map[string]int{
+				"Sent":  sent,
+				"Total": total,
+			},
+		}),
+		&gotgbot.SendMessageOpts{
+			DisableNotification: true,
+		},
+	)
+}
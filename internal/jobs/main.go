@@ -0,0 +1,194 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+const (
+	// idle workers check for new jobs this often,
+	// in case the job was created by another instance
+	pollInterval = 2 * time.Second
+	// doubled after every failed attempt
+	retryDelay = 30 * time.Second
+)
+
+// wakes up an idle worker when a job is created
+var wakeup = make(chan struct{}, 1)
+
+// closed when shutting down, the workers
+// stop claiming jobs once it is closed
+var (
+	stopping = make(chan struct{})
+	workers  sync.WaitGroup
+)
+
+// resumes the jobs left by the previous run,
+// then starts the workers processing them
+func Start(bot *gotgbot.Bot) {
+	resumeJobs(bot)
+
+	n := max(config.Env.JobWorkers, 1)
+	logger.L.Debugf("starting %d job workers", n)
+	for range n {
+		workers.Go(func() {
+			worker(bot)
+		})
+	}
+}
+
+// stops the workers from claiming new jobs
+var Stop = sync.OnceFunc(func() {
+	close(stopping)
+})
+
+// waits for the workers to finish their
+// running jobs, until the context is done
+func Wait(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// stores a job downloading the urls, sent in the given message
+func Enqueue(
+	bot *gotgbot.Bot,
+	message *gotgbot.Message,
+	urls []string,
+	audioOnly bool,
+) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	var userID int64
+	if message.From != nil {
+		userID = message.From.Id
+	}
+
+	jobID, err := database.Q().CreateJob(
+		context.Background(),
+		database.CreateJobParams{
+			ChatID:    message.Chat.Id,
+			UserID:    userID,
+			Urls:      urls,
+			AudioOnly: audioOnly,
+			Message:   data,
+			Priority:  jobPriority(message),
+			Status:    database.JobStatusPending,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create job: %w", err)
+	}
+
+	wakeWorker()
+	go notifyQueuePosition(bot, message, jobID)
+
+	return nil
+}
+
+// wakes up an idle worker, if any
+func wakeWorker() {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// jobs with higher priority are started first.
+// bot admins come first, then private chats.
+func jobPriority(message *gotgbot.Message) int32 {
+	if message.From != nil && slices.Contains(config.Env.Admins, message.From.Id) {
+		return 2
+	}
+	if message.Chat.Type == gotgbot.ChatTypePrivate {
+		return 1
+	}
+	return 0
+}
+
+// deletes the jobs of the user in the chat that did not
+// start yet, or every job in the chat if userID is zero.
+// returns the number of deleted jobs.
+func CancelPending(bot *gotgbot.Bot, chatID int64, userID int64) (int, error) {
+	jobIDs, err := database.Q().DeletePendingJobs(
+		context.Background(),
+		database.DeletePendingJobsParams{
+			ChatID: chatID,
+			UserID: userID,
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
+	for _, jobID := range jobIDs {
+		clearQueueNotice(bot, jobID)
+	}
+	return len(jobIDs), nil
+}
+
+// marks the jobs interrupted by a restart as pending
+// again, letting the original chat know about it.
+// this assumes a single instance of the bot is running.
+func resumeJobs(bot *gotgbot.Bot) {
+	jobs, err := database.Q().ResumeJobs(context.Background())
+	if err != nil {
+		logger.L.Errorf("failed to resume jobs: %v", err)
+		return
+	}
+	if len(jobs) == 0 {
+		return
+	}
+	logger.L.Infof("resuming %d jobs", len(jobs))
+
+	for _, job := range jobs {
+		ctx, err := contextFromJob(bot, &job)
+		if err != nil {
+			logger.L.Warnf("failed to decode job %d: %v", job.ID, err)
+			continue
+		}
+		chat, err := util.ChatFromContext(ctx)
+		if err != nil {
+			continue
+		}
+		localizer := localization.New(chat.Language)
+		ctx.EffectiveMessage.Reply(
+			bot, localizer.T(&i18n.LocalizeConfig{
+				MessageID: localization.JobResumedMessage.ID,
+			}),
+			&gotgbot.SendMessageOpts{
+				DisableNotification: true,
+			},
+		)
+	}
+}
+
+// rebuilds the update context from the stored message
+func contextFromJob(bot *gotgbot.Bot, job *database.Jobs) (*ext.Context, error) {
+	var message gotgbot.Message
+	if err := json.Unmarshal(job.Message, &message); err != nil {
+		return nil, err
+	}
+	return ext.NewContext(bot, &gotgbot.Update{Message: &message}, nil), nil
+}
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/localization"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+)
+
+// jobs starting within this delay
+// don't notify their queue position
+const queueNoticeDelay = 2 * time.Second
+
+// queue position messages, by job id. a nil message
+// means the notice is not sent yet. entries are
+// removed as soon as the job starts or is canceled.
+var (
+	queueNotices   = make(map[int64]*gotgbot.Message)
+	queueNoticesMu sync.Mutex
+)
+
+// tells the user the position of the job in
+// the queue, if it is still waiting after a short delay
+func notifyQueuePosition(bot *gotgbot.Bot, message *gotgbot.Message, jobID int64) {
+	queueNoticesMu.Lock()
+	queueNotices[jobID] = nil
+	queueNoticesMu.Unlock()
+
+	time.Sleep(queueNoticeDelay)
+
+	if !hasQueueNotice(jobID) {
+		// the job already started
+		return
+	}
+	position, err := database.Q().GetJobQueuePosition(context.Background(), jobID)
+	if err != nil || position == 0 {
+		clearQueueNotice(bot, jobID)
+		return
+	}
+
+	ctx := ext.NewContext(bot, &gotgbot.Update{Message: message}, nil)
+	chat, err := util.ChatFromContext(ctx)
+	if err != nil {
+		clearQueueNotice(bot, jobID)
+		return
+	}
+	localizer := localization.New(chat.Language)
+
+	notice, err := message.Reply(
+		bot, localizer.T(&i18n.LocalizeConfig{
+			MessageID: localization.QueuePositionMessage.ID,
+			TemplateData: map[string]int64{
+				"Position": position,
+			},
+		}),
+		&gotgbot.SendMessageOpts{
+			DisableNotification: true,
+		},
+	)
+	if err != nil {
+		clearQueueNotice(bot, jobID)
+		return
+	}
+
+	queueNoticesMu.Lock()
+	defer queueNoticesMu.Unlock()
+
+	if _, ok := queueNotices[jobID]; !ok {
+		// the job started while sending the notice
+		notice.Delete(bot, nil)
+		return
+	}
+	queueNotices[jobID] = notice
+}
+
+func hasQueueNotice(jobID int64) bool {
+	queueNoticesMu.Lock()
+	defer queueNoticesMu.Unlock()
+
+	_, ok := queueNotices[jobID]
+	return ok
+}
+
+// deletes the queue position message of the job, if any
+func clearQueueNotice(bot *gotgbot.Bot, jobID int64) {
+	queueNoticesMu.Lock()
+	notice := queueNotices[jobID]
+	delete(queueNotices, jobID)
+	queueNoticesMu.Unlock()
+
+	if notice != nil {
+		notice.Delete(bot, nil)
+	}
+}
@@ -0,0 +1,293 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/PaulSonOfLars/gotgbot/v2/ext"
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/core"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/extractors"
+	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func worker(bot *gotgbot.Bot) {
+	for {
+		select {
+		case <-stopping:
+			return
+		default:
+		}
+		job, err := claimJob(context.Background())
+		if err != nil {
+			if !errors.Is(err, pgx.ErrNoRows) {
+				logger.L.Errorf("failed to claim job: %v", err)
+			}
+			select {
+			case <-wakeup:
+			case <-time.After(pollInterval):
+			case <-stopping:
+				return
+			}
+			continue
+		}
+		clearQueueNotice(bot, job.ID)
+		runJob(bot, &job)
+	}
+}
+
+// claims the next job, skipping chats that
+// already reached their concurrent jobs limit
+func claimJob(ctx context.Context) (database.Jobs, error) {
+	tx, err := database.Conn().Begin(ctx)
+	if err != nil {
+		return database.Jobs{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := database.Q().WithTx(tx)
+
+	// claims are serialized, otherwise concurrent
+	// workers could exceed the limit of a chat
+	if err := qtx.LockJobClaims(ctx); err != nil {
+		return database.Jobs{}, err
+	}
+	job, err := qtx.ClaimJob(ctx, int32(max(config.Env.MaxChatJobs, 1)))
+	if err != nil {
+		return database.Jobs{}, err
+	}
+	return job, tx.Commit(ctx)
+}
+
+func runJob(bot *gotgbot.Bot, job *database.Jobs) {
+	ctx, err := contextFromJob(bot, job)
+	if err != nil {
+		logger.L.Errorf("failed to decode job %d: %v", job.ID, err)
+		failJob(job, err)
+		return
+	}
+
+	canRetry := int(job.Attempts) < config.Env.JobMaxAttempts
+
+	chat, err := util.ChatFromContext(ctx)
+	if err != nil {
+		logger.L.Errorf("failed to get settings from context: %v", err)
+		if canRetry {
+			retryJob(job, job.Urls, 0, err)
+		} else {
+			failJob(job, err)
+		}
+		return
+	}
+
+	var linkErrors []*core.LinkError
+	var retryURLs []string
+	var interruptedURLs []string
+	var lastErr error
+	var sent int
+
+	// links are processed one at a time,
+	// so that the results are sent in order
+	for i, url := range job.Urls {
+		extractorCtx, err := runLink(bot, ctx, chat, job.AudioOnly, url)
+		if extractorCtx == nil {
+			continue
+		}
+		if err == nil {
+			extractorCtx.CancelFunc()
+			sent++
+			continue
+		}
+
+		// links interrupted by a shutdown
+		// run again when the bot restarts
+		if isInterrupted(extractorCtx, err) {
+			extractorCtx.CancelFunc()
+			interruptedURLs = job.Urls[i:]
+			break
+		}
+
+		// the entries of a collection are queued as a new job,
+		// collections inside a collection are not expanded
+		entries := core.CollectionEntries(err)
+		if entries != nil && job.CollectionSize == 0 {
+			err = expandCollection(bot, ctx, chat, job, entries)
+			if err == nil {
+				extractorCtx.CancelFunc()
+				continue
+			}
+		}
+
+		canceled := core.IsCanceled(extractorCtx)
+		if !canceled && core.IsRetryableError(extractorCtx, err) {
+			lastErr = err
+			if canRetry {
+				extractorCtx.Warnf(
+					"job %d failed (attempt %d/%d): %v",
+					job.ID, job.Attempts, config.Env.JobMaxAttempts, err,
+				)
+				retryURLs = append(retryURLs, url)
+				extractorCtx.CancelFunc()
+				continue
+			}
+		}
+
+		message, errorID := core.ErrorMessage(extractorCtx, err)
+		extractorCtx.CancelFunc()
+
+		linkError := &core.LinkError{
+			Message: message,
+			ErrorID: errorID,
+		}
+		if len(job.Urls) > 1 {
+			linkError.URL = url
+		}
+		linkErrors = append(linkErrors, linkError)
+
+		// canceling stops the remaining links too
+		if canceled {
+			break
+		}
+	}
+
+	switch {
+	case len(interruptedURLs) > 0:
+		requeueJob(job, slices.Concat(retryURLs, interruptedURLs), sent)
+	case len(retryURLs) > 0:
+		retryJob(job, retryURLs, sent, lastErr)
+	case lastErr != nil:
+		// no attempts left, keep the job for inspection
+		failJob(job, lastErr)
+	default:
+		deleteJob(job)
+	}
+
+	core.HandleLinkErrors(bot, ctx, linkErrors)
+
+	if job.CollectionSize > 0 && len(retryURLs) == 0 && len(interruptedURLs) == 0 {
+		sendCollectionSummary(
+			bot, ctx, chat,
+			int(job.CollectionSent)+sent,
+			int(job.CollectionSize),
+		)
+	}
+}
+
+// downloads a single link of the job. the returned context
+// is nil when the link can't be downloaded anymore, otherwise
+// the caller must cancel it once done with the error.
+func runLink(
+	bot *gotgbot.Bot,
+	ctx *ext.Context,
+	chat *database.GetOrCreateChatRow,
+	audioOnly bool,
+	url string,
+) (*models.ExtractorContext, error) {
+	extractorCtx := extractors.FromURL(url)
+	if extractorCtx == nil || extractorCtx.Extractor == nil {
+		return nil, nil
+	}
+	// the extractor may have been disabled
+	// while the job was waiting
+	if slices.Contains(chat.DisabledExtractors, extractorCtx.Extractor.ID) {
+		extractorCtx.CancelFunc()
+		return nil, nil
+	}
+	extractorCtx.SetChat(chat)
+	extractorCtx.AudioOnly = audioOnly
+
+	err := util.SendTypingAction(bot, chat.ChatID)
+	if err != nil {
+		return extractorCtx, err
+	}
+	return extractorCtx, core.HandleDownloadTask(bot, ctx, extractorCtx)
+}
+
+func deleteJob(job *database.Jobs) {
+	err := database.Q().DeleteJob(context.Background(), job.ID)
+	if err != nil {
+		logger.L.Errorf("failed to delete job %d: %v", job.ID, err)
+	}
+}
+
+// queues the job again with the urls left, sent
+// is the number of urls completed by this attempt
+func retryJob(job *database.Jobs, urls []string, sent int, jobErr error) {
+	delay := retryDelay << max(job.Attempts-1, 0)
+	err := database.Q().RetryJob(
+		context.Background(),
+		database.RetryJobParams{
+			Urls: urls,
+			Sent: int32(sent),
+			RunAt: pgtype.Timestamptz{
+				Time:  time.Now().Add(delay),
+				Valid: true,
+			},
+			LastError: pgtype.Text{
+				String: jobErr.Error(),
+				Valid:  true,
+			},
+			ID: job.ID,
+		},
+	)
+	if err != nil {
+		logger.L.Errorf("failed to retry job %d: %v", job.ID, err)
+	}
+}
+
+// reports whether the link failed because the bot is shutting down
+func isInterrupted(extractorCtx *models.ExtractorContext, err error) bool {
+	if !core.IsShuttingDown() {
+		return false
+	}
+	return errors.Is(err, core.ErrShuttingDown) || core.IsCanceled(extractorCtx)
+}
+
+// queues the job again with the urls left, to run
+// as soon as possible. sent is the number of urls
+// completed before the job was interrupted.
+func requeueJob(job *database.Jobs, urls []string, sent int) {
+	err := database.Q().RetryJob(
+		context.Background(),
+		database.RetryJobParams{
+			Urls: urls,
+			Sent: int32(sent),
+			RunAt: pgtype.Timestamptz{
+				Time:  time.Now(),
+				Valid: true,
+			},
+			LastError: pgtype.Text{
+				String: core.ErrShuttingDown.Error(),
+				Valid:  true,
+			},
+			ID: job.ID,
+		},
+	)
+	if err != nil {
+		logger.L.Errorf("failed to requeue job %d: %v", job.ID, err)
+	}
+}
+
+func failJob(job *database.Jobs, jobErr error) {
+	err := database.Q().FailJob(
+		context.Background(),
+		database.FailJobParams{
+			LastError: pgtype.Text{
+				String: jobErr.Error(),
+				Valid:  true,
+			},
+			ID: job.ID,
+		},
+	)
+	if err != nil {
+		logger.L.Errorf("failed to mark job %d as failed: %v", job.ID, err)
+	}
+}
@@ -115,6 +115,114 @@ var (
 		ID:    "DeleteProcessedSettingsMessage",
 		Other: "when enabled, deletes the user's original message after successfully processing the link",
 	}
+	FormatPickerButton = &i18n.Message{
+		ID:    "FormatPickerButton",
+		Other: "format picker",
+	}
+	FormatPickerSettingsMessage = &i18n.Message{
+		ID:    "FormatPickerSettingsMessage",
+		Other: "when enabled, lets you choose the format (resolution, codec, size) before downloading. you can also add #pick to a single link",
+	}
+	FormatPickerMessage = &i18n.Message{
+		ID:    "FormatPickerMessage",
+		Other: "choose the format to download",
+	}
+	FormatPickerExpiredMessage = &i18n.Message{
+		ID:    "FormatPickerExpiredMessage",
+		Other: "this selection has expired, please send the link again",
+	}
+	FormatPickerDownloadingMessage = &i18n.Message{
+		ID:    "FormatPickerDownloadingMessage",
+		Other: "downloading the selected format... please wait",
+	}
+	VideoQualityButton = &i18n.Message{
+		ID:    "VideoQualityButton",
+		Other: "video quality",
+	}
+	VideoQualitySettingsMessage = &i18n.Message{
+		ID:    "VideoQualitySettingsMessage",
+		Other: "select the maximum video quality. lower qualities download faster and save data. if a video exceeds the size or duration limits, a smaller quality is used instead",
+	}
+	VideoQualityBestButton = &i18n.Message{
+		ID:    "VideoQualityBestButton",
+		Other: "best",
+	}
+	ReencodedCaptionMessage = &i18n.Message{
+		ID:    "ReencodedCaptionMessage",
+		Other: "re-encoded to fit the size limit: {{.From}} → {{.To}}",
+	}
+	ProgressExtractingMessage = &i18n.Message{
+		ID:    "ProgressExtractingMessage",
+		Other: "extracting media...",
+	}
+	ProgressDownloadingMessage = &i18n.Message{
+		ID:    "ProgressDownloadingMessage",
+		Other: "downloading...",
+	}
+	ProgressMergingMessage = &i18n.Message{
+		ID:    "ProgressMergingMessage",
+		Other: "merging video and audio...",
+	}
+	ProgressProcessingMessage = &i18n.Message{
+		ID:    "ProgressProcessingMessage",
+		Other: "processing...",
+	}
+	ProgressUploadingMessage = &i18n.Message{
+		ID:    "ProgressUploadingMessage",
+		Other: "uploading...",
+	}
+	ProgressETAMessage = &i18n.Message{
+		ID:    "ProgressETAMessage",
+		Other: "eta {{.ETA}}",
+	}
+	CancelButton = &i18n.Message{
+		ID:    "CancelButton",
+		Other: "cancel",
+	}
+	DownloadCanceledMessage = &i18n.Message{
+		ID:    "DownloadCanceledMessage",
+		Other: "download canceled",
+	}
+	NoActiveDownloadsMessage = &i18n.Message{
+		ID:    "NoActiveDownloadsMessage",
+		Other: "you have no active downloads to cancel",
+	}
+	JobResumedMessage = &i18n.Message{
+		ID:    "JobResumedMessage",
+		Other: "the bot was restarted, resuming your download...",
+	}
+	QueuePositionMessage = &i18n.Message{
+		ID:    "QueuePositionMessage",
+		Other: "your download is queued, position {{.Position}}",
+	}
+	CollectionConfirmMessage = &i18n.Message{
+		ID:    "CollectionConfirmMessage",
+		Other: "this link contains {{.Count}} entries, do you want to download them?",
+	}
+	CollectionConfirmButton = &i18n.Message{
+		ID:    "CollectionConfirmButton",
+		Other: "download",
+	}
+	CollectionQueuedMessage = &i18n.Message{
+		ID:    "CollectionQueuedMessage",
+		Other: "{{.Count}} entries queued",
+	}
+	CollectionSummaryMessage = &i18n.Message{
+		ID:    "CollectionSummaryMessage",
+		Other: "sent {{.Sent}} of {{.Total}} entries",
+	}
+	CaptionTemplateMessage = &i18n.Message{
+		ID:    "CaptionTemplateMessage",
+		Other: "<b>caption header</b>\n<code>{{.Header}}</code>\n\n<b>caption description</b>\n<code>{{.Description}}</code>\n\nchange them with <code>/caption header template</code> or <code>/caption description template</code>, use <code>-</code> as template to leave one empty, or restore the default ones with <code>/caption reset</code>.\n\nplaceholders: {{.Placeholders}}\ntext between <code>{{.SectionStart}}</code> and <code>{{.SectionEnd}}</code> is shown only when the value is known",
+	}
+	CaptionTemplatePreviewMessage = &i18n.Message{
+		ID:    "CaptionTemplatePreviewMessage",
+		Other: "saving caption template, captions will look like this:",
+	}
+	CaptionTemplateSavedMessage = &i18n.Message{
+		ID:    "CaptionTemplateSavedMessage",
+		Other: "caption template saved, captions will look like this:",
+	}
 	SupportedExtractorsMessage = &i18n.Message{
 		ID:    "SupportedExtractorsMessage",
 		Other: "list of supported extractors by the bot",
@@ -191,4 +299,16 @@ var (
 		ID:    "ErrorPermissionDenied",
 		Other: "the bot does not have sufficient permissions to send this media. please grant the necessary permissions and try again",
 	}
+	ErrorCollectionUnsupported = &i18n.Message{
+		ID:    "ErrorCollectionUnsupported",
+		Other: "playlists and albums can't be downloaded here, send the link in a chat instead",
+	}
+	ErrorCollectionLimitExceeded = &i18n.Message{
+		ID:    "ErrorCollectionLimitExceeded",
+		Other: "too many entries are already queued in this chat, try again later",
+	}
+	ErrorInvalidCaptionTemplate = &i18n.Message{
+		ID:    "ErrorInvalidCaptionTemplate",
+		Other: "invalid caption template: {{.Reason}}",
+	}
 )
@@ -17,7 +17,17 @@ func Init() {
 	if err != nil {
 		panic(err)
 	}
-	logger, err := newZapLogger()
+	initWithConsole(os.Stdout, true)
+}
+
+// logs to stderr only, leaving stdout to the command
+// output and the working directory untouched
+func InitStderr() {
+	initWithConsole(os.Stderr, false)
+}
+
+func initWithConsole(console *os.File, logToFile bool) {
+	logger, err := newZapLogger(console, logToFile)
 	if err != nil {
 		panic(err)
 	}
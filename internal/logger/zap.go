@@ -8,11 +8,7 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
-func newZapLogger() (*zap.Logger, error) {
-	logFile, err := os.OpenFile("logs/app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		return nil, err
-	}
+func newZapLogger(console *os.File, logToFile bool) (*zap.Logger, error) {
 	simpleTimeEncoder := func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 		enc.AppendString(t.Format("15:04:05"))
 	}
@@ -32,9 +28,16 @@ func newZapLogger() (*zap.Logger, error) {
 	}
 	consoleCore := zapcore.NewCore(
 		zapcore.NewConsoleEncoder(consoleEncoderConfig),
-		zapcore.Lock(os.Stdout),
+		zapcore.Lock(console),
 		atomicLevel,
 	)
+	if !logToFile {
+		return zap.New(consoleCore), nil
+	}
+	logFile, err := os.OpenFile("logs/app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
 	fileCore := zapcore.NewCore(
 		zapcore.NewConsoleEncoder(fileEncoderConfig),
 		zapcore.AddSync(logFile),
@@ -41,8 +41,23 @@ type ExtractorContext struct {
 	Context    context.Context
 	CancelFunc context.CancelFunc
 
+	// format explicitly chosen by the user, if any.
+	// takes precedence over the default format selection
+	FormatID string
+
+	// only the audio is sent, extracting
+	// it from the video when needed
+	AudioOnly bool
+
+	// the media is saved locally instead of being
+	// sent, so the telegram upload limits don't apply
+	Standalone bool
+
 	// allows plugins to download additional formats
 	DownloadFunc func(*ExtractorContext, int, *MediaFormat) (*DownloadedFormat, error)
+
+	// receives progress updates of the task, if set
+	ProgressFunc func(phase ProgressPhase, current int64, total int64)
 }
 
 func (e *ExtractorContext) Debugf(format string, args ...interface{}) {
@@ -77,6 +92,23 @@ func (e *ExtractorContext) Errorf(format string, args ...interface{}) {
 	logger.L.Errorf(fmt.Sprintf("[%s] %s: %s", e.ContentURL, e.Extractor.ID, format), args...)
 }
 
+// reports the progress of the current phase.
+// current and total are in bytes for transfers and
+// in seconds for processing; total is zero when unknown.
+func (e *ExtractorContext) ReportProgress(phase ProgressPhase, current int64, total int64) {
+	if e.ProgressFunc == nil {
+		return
+	}
+	e.ProgressFunc(phase, current, total)
+}
+
+// returns a function reporting the progress of the given phase
+func (e *ExtractorContext) PhaseProgress(phase ProgressPhase) func(int64, int64) {
+	return func(current int64, total int64) {
+		e.ReportProgress(phase, current, total)
+	}
+}
+
 func (e *ExtractorContext) Key() string {
 	return e.Extractor.ID + "/" + e.ContentID
 }
@@ -96,6 +128,10 @@ func (e *ExtractorContext) NewMedia() *Media {
 type ExtractorResponse struct {
 	URL   string
 	Media *Media
+
+	// urls of the entries of a collection (playlist, album,
+	// profile), each downloaded as a separate task, in order
+	Entries []string
 }
 
 // peforms an HTTP request with the given method,
@@ -2,12 +2,17 @@ package models
 
 import (
 	"os"
+	"sync"
 
 	"github.com/govdbot/govd/internal/logger"
 )
 
 type FilesTracker struct {
 	Files []string
+
+	// cleanup may run while shutting
+	// down, concurrently with the task
+	mu sync.Mutex
 }
 
 func NewFilesTracker() *FilesTracker {
@@ -17,10 +22,14 @@ func NewFilesTracker() *FilesTracker {
 }
 
 func (ft *FilesTracker) Add(files ...string) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
 	ft.Files = append(ft.Files, files...)
 }
 
 func (ft *FilesTracker) Cleanup() {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
 	for _, fileName := range ft.Files {
 		info, err := os.Stat(fileName)
 		if err != nil {
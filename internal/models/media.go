@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/PaulSonOfLars/gotgbot/v2"
 	"github.com/google/uuid"
@@ -19,6 +20,16 @@ type Media struct {
 	Caption     string
 	NSFW        bool
 
+	// optional details of the content, shown in
+	// the caption. zero values are unknown
+	Title        string
+	AuthorName   string
+	AuthorHandle string
+	UploadedAt   time.Time
+	LikeCount    int64
+	ViewCount    int64
+	CommentCount int64
+
 	Items []*MediaItem
 }
 
@@ -65,6 +76,10 @@ type MediaFormat struct {
 	InitSegment      string
 	Segments         []string
 	DecryptionKey    *DecryptionKey
+
+	// video quality limits of the chats (0 for none)
+	// the stored format was selected for
+	SelectedFor []int32
 }
 
 type DownloadedFormat struct {
@@ -73,6 +88,10 @@ type DownloadedFormat struct {
 	FilePath          string
 	ThumbnailFilePath string
 	Error             error
+
+	// label of the format before it was
+	// re-encoded to fit the upload size limit
+	ReencodedFrom string
 }
 
 // returns the file extension and the InputMedia type.
@@ -151,6 +170,45 @@ func (f *MediaFormat) ToString() string {
 	return "[" + strings.Join(parts, ", ") + "]"
 }
 
+// returns the shortest side of the video,
+// which matches the common "p" notation
+// for both landscape and portrait videos.
+func (f *MediaFormat) GetQuality() int32 {
+	if f.Width > 0 && f.Height > 0 {
+		return min(f.Width, f.Height)
+	}
+	return f.Height
+}
+
+// returns a short human-readable description
+// of the format, used for format selection buttons.
+func (f *MediaFormat) GetLabel() string {
+	parts := make([]string, 0, 3)
+
+	switch {
+	case f.VideoCodec != "" && f.GetQuality() > 0:
+		parts = append(parts, fmt.Sprintf("%dp", f.GetQuality()))
+	case f.VideoCodec != "":
+		parts = append(parts, "video")
+	case f.AudioCodec != "":
+		parts = append(parts, "audio")
+	default:
+		parts = append(parts, string(f.Type))
+	}
+	switch {
+	case f.VideoCodec != "":
+		parts = append(parts, string(f.VideoCodec))
+	case f.AudioCodec != "":
+		parts = append(parts, string(f.AudioCodec))
+	}
+	if fileSize := f.formatFileSize(); fileSize != "" {
+		parts = append(parts, fileSize)
+	} else if bitrate := f.formatBitrate(); bitrate != "" {
+		parts = append(parts, bitrate)
+	}
+	return strings.Join(parts, " · ")
+}
+
 func (f *MediaFormat) GetFileName() string {
 	ext, _ := f.GetInfo()
 	if f.Type == database.MediaTypeAudio && f.Title != "" && f.Artist != "" {
@@ -196,6 +254,64 @@ func (mi *MediaItem) GetDefaultFormat() *MediaFormat {
 }
 
 func (mi *MediaItem) GetDefaultVideoFormat() *MediaFormat {
+	formats := mi.GetVideoFormats()
+	if len(formats) == 0 {
+		return nil
+	}
+	return formats[0]
+}
+
+// returns the best video format not exceeding the given
+// quality (e.g. 720 for 720p, 0 means no limit).
+// when the selected format is rejected by isValid
+// (e.g. too large), smaller formats are tried instead.
+func (mi *MediaItem) GetVideoFormatWithLimit(
+	maxQuality int32,
+	isValid func(*MediaFormat) bool,
+) *MediaFormat {
+	formats := mi.GetVideoFormats()
+	if len(formats) == 0 {
+		return nil
+	}
+	candidates := formats
+	if maxQuality > 0 {
+		// formats of unknown quality may exceed the limit
+		candidates = slices.DeleteFunc(slices.Clone(formats), func(format *MediaFormat) bool {
+			return format.GetQuality() == 0 || format.GetQuality() > maxQuality
+		})
+		if len(candidates) == 0 {
+			// no format under the limit,
+			// use the smallest one available
+			candidates = []*MediaFormat{lowestQualityFormat(formats)}
+		}
+	}
+	for _, format := range candidates {
+		if isValid(format) {
+			return format
+		}
+	}
+	return candidates[0]
+}
+
+// returns the format with the lowest known quality,
+// or the first one when the quality of none is known
+func lowestQualityFormat(formats []*MediaFormat) *MediaFormat {
+	lowest := formats[0]
+	for _, format := range formats {
+		quality := format.GetQuality()
+		if quality == 0 {
+			continue
+		}
+		if lowest.GetQuality() == 0 || quality < lowest.GetQuality() {
+			lowest = format
+		}
+	}
+	return lowest
+}
+
+// returns the video formats sorted from the best
+// to the worst, preferring avc over other codecs.
+func (mi *MediaItem) GetVideoFormats() []*MediaFormat {
 	filtered := mi.FilterFormats(func(format *MediaFormat) bool {
 		return format.VideoCodec == database.MediaCodecAvc
 	})
@@ -221,8 +337,7 @@ func (mi *MediaItem) GetDefaultVideoFormat() *MediaFormat {
 		}
 		return 0
 	})
-	bestFormat := filtered[0]
-	return bestFormat
+	return filtered
 }
 
 func (mi *MediaItem) GetDefaultAudioFormat() *MediaFormat {
@@ -282,18 +397,14 @@ func (format *MediaFormat) GetInputMedia(
 
 	_, inputMediaType := format.GetInfo()
 
-	fileObj, err := os.Open(filePath)
+	fileInputMedia, err := inputFileByPath(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file: %w", err)
+		return nil, err
 	}
 
-	fileInputMedia := gotgbot.InputFileByReader(
-		filepath.Base(filePath),
-		fileObj,
-	)
-
 	var thumbnailFileInputMedia gotgbot.InputFile
 	if thumbnailFilePath != "" {
+		// thumbnails can only be uploaded, even to a local server
 		thumbnailFileObj, err := os.Open(thumbnailFilePath)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open file: %w", err)
@@ -427,8 +538,11 @@ func (f *MediaFormat) formatBitrate() string {
 }
 
 func (f *MediaFormat) formatFileSize() string {
-	bytes := f.FileSize
+	return FormatFileSize(f.FileSize)
+}
 
+// returns a human-readable file size (e.g. 4.2MB)
+func FormatFileSize(bytes int64) string {
 	if bytes == 0 {
 		return ""
 	}
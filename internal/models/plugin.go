@@ -1,6 +1,49 @@
 package models
 
+import (
+	"slices"
+
+	"github.com/govdbot/govd/internal/database"
+)
+
+// the stage of the post-processing in which a plugin runs.
+// plugins run ordered by stage, so that each one receives
+// the file in the shape it expects: streams are merged before
+// being converted, and metadata is written on the final file.
+type PluginStage int
+
+const (
+	// combines separate streams into one file
+	PluginStageMerge PluginStage = iota
+	// changes the media type of the file
+	PluginStageConvert
+	// re-encodes the streams of the file
+	PluginStageEncode
+	// alters the content of the streams
+	PluginStageFilter
+	// shrinks the file to the upload size limit
+	PluginStageFit
+	// replaces the thumbnail of the file
+	PluginStageThumbnail
+	// writes metadata, without touching the streams
+	PluginStageMetadata
+)
+
 type Plugin struct {
-	ID      string
+	ID    string
+	Stage PluginStage
+
+	// media types the plugin accepts, any when empty
+	Inputs []database.MediaType
+	// media type of the produced file, set on the
+	// format once the plugin succeeds. the same
+	// as the input when empty
+	Output database.MediaType
+
 	RunFunc func(*ExtractorContext, *MediaItem, *DownloadedFormat) error
 }
+
+// reports whether the plugin can process files of the media type
+func (p *Plugin) Accepts(mediaType database.MediaType) bool {
+	return len(p.Inputs) == 0 || slices.Contains(p.Inputs, mediaType)
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"io"
+)
+
+type ProgressPhase string
+
+const (
+	ProgressPhaseExtracting  ProgressPhase = "extracting"
+	ProgressPhaseDownloading ProgressPhase = "downloading"
+	ProgressPhaseMerging     ProgressPhase = "merging"
+	ProgressPhaseProcessing  ProgressPhase = "processing"
+	ProgressPhaseUploading   ProgressPhase = "uploading"
+)
+
+// reports whether the progress of the
+// phase is expressed in transferred bytes
+func (p ProgressPhase) IsTransfer() bool {
+	return p == ProgressPhaseDownloading || p == ProgressPhaseUploading
+}
+
+type ProgressWriter struct {
+	writer  io.Writer
+	total   int64
+	written int64
+	report  func(current int64, total int64)
+}
+
+// wraps the writer, calling report with
+// the number of bytes written so far
+func NewProgressWriter(
+	writer io.Writer,
+	total int64,
+	report func(current int64, total int64),
+) *ProgressWriter {
+	return &ProgressWriter{
+		writer: writer,
+		total:  total,
+		report: report,
+	}
+}
+
+func (w *ProgressWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	if w.report != nil {
+		w.report(w.written, w.total)
+	}
+	return n, err
+}
@@ -5,3 +5,10 @@ type TaskResult struct {
 	Formats  []*DownloadedFormat
 	IsStored bool
 }
+
+type PickerTask struct {
+	ExtractorCtx *ExtractorContext
+	Media        *Media
+	UserID       int64
+	IsSpoiler    bool
+}
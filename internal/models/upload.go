@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/PaulSonOfLars/gotgbot/v2"
+	"github.com/govdbot/govd/internal/config"
+)
+
+// returns the file to upload. a local bot api server is
+// given the path of the file, so it reads it from the shared
+// volume instead of receiving its contents over http.
+func inputFileByPath(path string) (gotgbot.InputFileOrString, error) {
+	if config.Env.BotAPILocal {
+		serverPath, err := botAPIPath(path)
+		if err != nil {
+			return nil, err
+		}
+		return gotgbot.InputFileByURL("file://" + filepath.ToSlash(serverPath)), nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return gotgbot.InputFileByReader(filepath.Base(path), file), nil
+}
+
+// returns the absolute path of the file as seen by the
+// bot api server, which may mount the downloads directory
+// somewhere else than the bot does
+func botAPIPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve file path: %w", err)
+	}
+	if config.Env.BotAPIDownloadsDir == "" {
+		return absPath, nil
+	}
+	downloadsDir, err := filepath.Abs(config.Env.DownloadsDirectory)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve downloads directory: %w", err)
+	}
+	relPath, err := filepath.Rel(downloadsDir, absPath)
+	if err != nil || relPath == ".." || strings.HasPrefix(relPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("file is outside the downloads directory: %s", path)
+	}
+	return filepath.Join(config.Env.BotAPIDownloadsDir, relPath), nil
+}
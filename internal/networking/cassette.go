@@ -0,0 +1,305 @@
+package networking
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"github.com/govdbot/govd/internal/logger"
+)
+
+type CassetteMode string
+
+const (
+	// requests are sent and written to the cassette
+	CassetteModeRecord CassetteMode = "record"
+	// requests are served from the cassette, offline
+	CassetteModeReplay CassetteMode = "replay"
+)
+
+const redactedValue = "[redacted]"
+
+// headers never written to cassettes
+var redactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Csrf-Token",
+	"X-Ig-Www-Claim",
+}
+
+// stores the http interactions of a task in a directory,
+// one json file per request and its response body aside,
+// so they can be replayed later without network access.
+type Cassette struct {
+	Dir  string
+	Mode CassetteMode
+
+	mu sync.Mutex
+	// requests seen so far, by key. repeated requests
+	// are stored and replayed in order.
+	counts map[string]int
+}
+
+type cassetteInteraction struct {
+	Request  *cassetteRequest  `json:"request"`
+	Response *cassetteResponse `json:"response"`
+}
+
+type cassetteRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type cassetteResponse struct {
+	StatusCode int         `json:"status_code"`
+	Headers    http.Header `json:"headers"`
+	// final url of the request, after redirects
+	URL string `json:"url"`
+}
+
+func NewCassette(dir string, mode CassetteMode) *Cassette {
+	return &Cassette{
+		Dir:    dir,
+		Mode:   mode,
+		counts: make(map[string]int),
+	}
+}
+
+// wraps the client so its requests go through
+// the cassette. a nil cassette returns the client.
+func (c *Cassette) Wrap(client HTTPClientInterface) HTTPClientInterface {
+	if c == nil {
+		return client
+	}
+	return &cassetteClient{
+		cassette: c,
+		client:   client,
+	}
+}
+
+type cassetteClient struct {
+	cassette *Cassette
+	client   HTTPClientInterface
+}
+
+func (c *cassetteClient) Do(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	pathKey, key := cassetteKeys(req, body)
+	index := c.cassette.next(key)
+
+	if c.cassette.Mode == CassetteModeReplay {
+		return c.cassette.replay(req, pathKey, key, index)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("%s-%s-%d", pathKey, key, index)
+	return c.cassette.record(req, body, resp, name)
+}
+
+func (c *Cassette) next(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	index := c.counts[key]
+	c.counts[key]++
+	return index
+}
+
+// writes the interaction, the response body is written
+// to the cassette while the caller reads it
+func (c *Cassette) record(
+	req *http.Request,
+	body []byte,
+	resp *http.Response,
+	name string,
+) (*http.Response, error) {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+
+	finalURL := req.URL.String()
+	if resp.Request != nil {
+		finalURL = resp.Request.URL.String()
+	}
+	interaction := &cassetteInteraction{
+		Request: &cassetteRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: redactHeaders(req.Header),
+			Body:    string(body),
+		},
+		Response: &cassetteResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    redactHeaders(resp.Header),
+			URL:        finalURL,
+		},
+	}
+	data, err := sonic.ConfigDefault.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode interaction: %w", err)
+	}
+	jsonPath := filepath.Join(c.Dir, name+".json")
+	if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write interaction: %w", err)
+	}
+
+	bodyFile, err := os.Create(filepath.Join(c.Dir, name+".body"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create response body file: %w", err)
+	}
+	resp.Body = &recordingBody{
+		reader: io.TeeReader(resp.Body, bodyFile),
+		body:   resp.Body,
+		file:   bodyFile,
+	}
+	logger.L.Debugf("recording %s %s to %s", req.Method, req.URL, jsonPath)
+	return resp, nil
+}
+
+// serves the recorded response of the request. when the
+// exact request was not recorded, a request to the same
+// path is used, so changing query parameters still match.
+func (c *Cassette) replay(
+	req *http.Request,
+	pathKey string,
+	key string,
+	index int,
+) (*http.Response, error) {
+	name := c.find(pathKey+"-"+key+"-", index)
+	if name == "" {
+		name = c.find(pathKey+"-", index)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL)
+	}
+
+	data, err := os.ReadFile(filepath.Join(c.Dir, name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read interaction: %w", err)
+	}
+	var interaction cassetteInteraction
+	if err := sonic.ConfigDefault.Unmarshal(data, &interaction); err != nil {
+		return nil, fmt.Errorf("failed to parse interaction: %w", err)
+	}
+	if interaction.Response == nil {
+		return nil, fmt.Errorf("interaction %s has no response", name)
+	}
+
+	bodyFile, err := os.Open(filepath.Join(c.Dir, name+".body"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open response body: %w", err)
+	}
+	stat, err := bodyFile.Stat()
+	if err != nil {
+		bodyFile.Close()
+		return nil, fmt.Errorf("failed to open response body: %w", err)
+	}
+
+	finalReq := req
+	if interaction.Response.URL != "" {
+		finalURL, err := url.Parse(interaction.Response.URL)
+		if err == nil {
+			finalReq = req.Clone(req.Context())
+			finalReq.URL = finalURL
+		}
+	}
+
+	statusCode := interaction.Response.StatusCode
+	logger.L.Debugf("replaying %s %s from %s", req.Method, req.URL, name)
+	return &http.Response{
+		StatusCode:    statusCode,
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers,
+		Body:          bodyFile,
+		ContentLength: stat.Size(),
+		Request:       finalReq,
+	}, nil
+}
+
+// returns the name of the recorded interaction with the
+// prefix at the given index, or the last one if there
+// are fewer. empty if none was recorded.
+func (c *Cassette) find(prefix string, index int) string {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, prefix+"*.json"))
+	if err != nil || len(matches) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(matches))
+	for _, match := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(match), ".json"))
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return interactionIndex(a) - interactionIndex(b)
+	})
+	return names[min(index, len(names)-1)]
+}
+
+func interactionIndex(name string) int {
+	i := strings.LastIndex(name, "-")
+	index, _ := strconv.Atoi(name[i+1:])
+	return index
+}
+
+// returns the keys identifying the request: one for its path only,
+// and one for the whole request, including the range and the body
+func cassetteKeys(req *http.Request, body []byte) (string, string) {
+	pathHash := sha256.Sum256([]byte(
+		req.Method + " " + req.URL.Host + req.URL.Path,
+	))
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.String() + "\n"))
+	hash.Write([]byte(req.Header.Get("Range") + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(pathHash[:6]), hex.EncodeToString(hash.Sum(nil)[:6])
+}
+
+func redactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// tees the response body to the cassette. the body is
+// recorded as far as the caller reads it.
+type recordingBody struct {
+	reader io.Reader
+	body   io.ReadCloser
+	file   *os.File
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	return b.reader.Read(p)
+}
+
+func (b *recordingBody) Close() error {
+	b.file.Close()
+	return b.body.Close()
+}
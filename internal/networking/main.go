@@ -43,6 +43,10 @@ func NewHTTPClient(options *NewHTTPClientOptions) *HTTPClient {
 	}
 
 	client.DownloadProxy = options.DownloadProxy
+
+	// nil when cassettes are disabled
+	client.Cassette = options.Cassette
+	client.Client = options.Cassette.Wrap(client.Client)
 	return client
 }
 
@@ -83,5 +87,7 @@ func (c *HTTPClient) AsDownloadClient() *HTTPClient {
 		}
 		client.DisableProxy = true
 	}
+	client.Cassette = c.Cassette
+	client.Client = c.Cassette.Wrap(client.Client)
 	return client
 }
@@ -17,6 +17,7 @@ type HTTPClient struct {
 	EdgeProxy     string
 	DownloadProxy string
 	DisableProxy  bool
+	Cassette      *Cassette
 }
 
 type NewHTTPClientOptions struct {
@@ -27,6 +28,7 @@ type NewHTTPClientOptions struct {
 	DownloadProxy string
 	Impersonate   bool
 	DisableProxy  bool
+	Cassette      *Cassette
 }
 
 type RequestParams struct {
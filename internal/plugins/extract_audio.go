@@ -0,0 +1,73 @@
+package plugins
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/govdbot/govd/internal/util/libav"
+)
+
+var ExtractAudio = &models.Plugin{
+	ID:    "extract_audio",
+	Stage: models.PluginStageConvert,
+
+	Inputs: []database.MediaType{database.MediaTypeVideo},
+	Output: database.MediaTypeAudio,
+
+	RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
+		filePath := format.FilePath
+
+		// aac and mp3 streams can be copied as they are,
+		// everything else is transcoded to mp3
+		audioCodec := format.Format.AudioCodec
+		transcode := audioCodec != database.MediaCodecAac &&
+			audioCodec != database.MediaCodecMp3
+		if transcode {
+			audioCodec = database.MediaCodecMp3
+		}
+
+		format.Format.VideoCodec = ""
+		format.Format.AudioCodec = audioCodec
+		format.Format.Width = 0
+		format.Format.Height = 0
+
+		ext, _ := format.Format.GetInfo()
+		outputPath := strings.TrimSuffix(
+			filePath,
+			filepath.Ext(filePath),
+		) + "." + string(ext)
+		ctx.FilesTracker.Add(outputPath)
+
+		ctx.ReportProgress(models.ProgressPhaseProcessing, 0, 0)
+
+		err := libav.ExtractAudio(ctx.Context, filePath, outputPath, transcode)
+		if err != nil {
+			return err
+		}
+		format.FilePath = outputPath
+
+		// telegram expects smaller thumbnails for audio
+		if format.ThumbnailFilePath != "" {
+			data, err := os.ReadFile(format.ThumbnailFilePath)
+			if err != nil {
+				return fmt.Errorf("failed to read thumbnail: %w", err)
+			}
+			_, err = util.ImgToJPEG(
+				bytes.NewReader(data),
+				format.ThumbnailFilePath,
+				320,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to resize thumbnail: %w", err)
+			}
+		}
+
+		return nil
+	},
+}
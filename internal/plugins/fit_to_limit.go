@@ -0,0 +1,143 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/govdbot/govd/internal/util/libav"
+)
+
+const (
+	// below this, the video is not worth watching
+	minFitVideoBitrate = 150_000
+	fitAudioBitrate    = 96_000
+)
+
+var FitToLimit = &models.Plugin{
+	ID:    "fit_to_limit",
+	Stage: models.PluginStageFit,
+
+	Inputs: []database.MediaType{database.MediaTypeVideo},
+
+	RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
+		// the upload limit doesn't apply to local files
+		if ctx.Standalone {
+			return nil
+		}
+		filePath := format.FilePath
+
+		info, err := os.Stat(filePath)
+		if err != nil {
+			return fmt.Errorf("failed to stat file: %w", err)
+		}
+		format.Format.FileSize = info.Size()
+
+		limit := util.MaxUploadSize()
+		if format.Format.FileSize <= limit {
+			return nil
+		}
+		duration := int64(format.Format.Duration)
+		if duration <= 0 {
+			return util.ErrFileTooLarge
+		}
+
+		// leave some room for the container overhead
+		targetSize := limit * 95 / 100
+		totalBitrate := targetSize * 8 / duration
+
+		var audioBitrate int64
+		if format.Format.AudioCodec != "" {
+			audioBitrate = fitAudioBitrate
+		}
+		videoBitrate := totalBitrate - audioBitrate
+		if videoBitrate < minFitVideoBitrate {
+			return util.ErrFileTooLarge
+		}
+		width, height := fitResolution(
+			format.Format.Width,
+			format.Format.Height,
+			videoBitrate,
+		)
+
+		outputPath := strings.TrimSuffix(
+			filePath,
+			filepath.Ext(filePath),
+		) + "_fit.mp4"
+		ctx.FilesTracker.Add(outputPath)
+
+		ctx.Debugf(
+			"re-encoding %d bytes to fit %d bytes (%dx%d)",
+			format.Format.FileSize, limit, width, height,
+		)
+
+		originalLabel := format.Format.GetLabel()
+
+		err = libav.EncodeWithBitrate(
+			ctx.Context, filePath, outputPath,
+			videoBitrate, audioBitrate,
+			width, height,
+			encodingProgress(ctx, format),
+		)
+		if err != nil {
+			return err
+		}
+
+		info, err = os.Stat(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to stat file: %w", err)
+		}
+		if info.Size() > limit {
+			return util.ErrFileTooLarge
+		}
+
+		format.FilePath = outputPath
+		format.Format.FileSize = info.Size()
+		format.Format.VideoCodec = database.MediaCodecAvc
+		if format.Format.AudioCodec != "" {
+			format.Format.AudioCodec = database.MediaCodecAac
+		}
+		if width > 0 && height > 0 {
+			format.Format.Width = width
+			format.Format.Height = height
+		}
+		format.ReencodedFrom = originalLabel
+
+		return nil
+	},
+}
+
+// returns the output resolution for the given video bitrate,
+// as low bitrates look better at lower resolutions.
+// returns zero values when the resolution should be kept.
+func fitResolution(width int32, height int32, bitrate int64) (int32, int32) {
+	if width <= 0 || height <= 0 {
+		return 0, 0
+	}
+	var quality int32
+	switch {
+	case bitrate < 400_000:
+		quality = 360
+	case bitrate < 800_000:
+		quality = 480
+	case bitrate < 1_500_000:
+		quality = 720
+	case bitrate < 3_000_000:
+		quality = 1080
+	default:
+		return 0, 0
+	}
+	shortSide := min(width, height)
+	if shortSide <= quality {
+		return 0, 0
+	}
+	// keep the aspect ratio, with even dimensions
+	scale := func(v int32) int32 {
+		return int32(int64(v)*int64(quality)/int64(shortSide)) / 2 * 2
+	}
+	return scale(width), scale(height)
+}
@@ -3,6 +3,7 @@ package plugins
 import (
 	"os"
 
+	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/models"
 
 	"fmt"
@@ -11,11 +12,19 @@ import (
 )
 
 var ID3 = &models.Plugin{
-	ID: "id3",
+	ID:    "id3",
+	Stage: models.PluginStageMetadata,
+
+	Inputs: []database.MediaType{database.MediaTypeAudio},
+
 	RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
 		if format.FilePath == "" {
 			return fmt.Errorf("file path is empty")
 		}
+		// id3 tags are only supported by mp3 files
+		if format.Format.AudioCodec != database.MediaCodecMp3 {
+			return nil
+		}
 		tag, err := id3v2.Open(
 			format.FilePath,
 			id3v2.Options{},
@@ -0,0 +1,92 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util/libav"
+)
+
+const loudnormID = "loudnorm"
+
+// normalizes the loudness of the audio, so that files
+// from different sources play at the same volume.
+// args: i (integrated loudness, LUFS), tp (true peak, dBTP)
+// and lra (loudness range, LU), defaulting to the ebu r128
+// values commonly used by streaming services.
+func newLoudnorm(args map[string]string) (*models.Plugin, error) {
+	if err := checkArgs(args, "i", "tp", "lra"); err != nil {
+		return nil, err
+	}
+	integrated, err := floatArg(args, "i", -16)
+	if err != nil {
+		return nil, err
+	}
+	truePeak, err := floatArg(args, "tp", -1.5)
+	if err != nil {
+		return nil, err
+	}
+	loudnessRange, err := floatArg(args, "lra", 11)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Plugin{
+		ID:    loudnormID,
+		Stage: models.PluginStageFilter,
+
+		Inputs: []database.MediaType{database.MediaTypeVideo, database.MediaTypeAudio},
+
+		RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
+			if format.Format.AudioCodec == "" {
+				return nil
+			}
+			filePath := format.FilePath
+
+			outputPath := strings.TrimSuffix(
+				filePath,
+				filepath.Ext(filePath),
+			) + "_loudnorm" + filepath.Ext(filePath)
+			ctx.FilesTracker.Add(outputPath)
+
+			encoder, audioCodec := audioEncoder(format.Format.AudioCodec)
+
+			err := libav.NormalizeLoudness(
+				ctx.Context, filePath, outputPath,
+				integrated, truePeak, loudnessRange,
+				encoder, encodingProgress(ctx, format),
+			)
+			if err != nil {
+				return err
+			}
+			format.FilePath = outputPath
+
+			format.Format.AudioCodec = audioCodec
+			if info, err := os.Stat(outputPath); err == nil {
+				format.Format.FileSize = info.Size()
+			}
+
+			return nil
+		},
+	}, nil
+}
+
+// returns the ffmpeg encoder that keeps the audio codec, so that
+// the file still fits its container. other codecs become aac.
+func audioEncoder(codec database.MediaCodec) (string, database.MediaCodec) {
+	switch codec {
+	case database.MediaCodecMp3:
+		return "libmp3lame", codec
+	case database.MediaCodecOpus:
+		return "libopus", codec
+	case database.MediaCodecVorbis:
+		return "libvorbis", codec
+	case database.MediaCodecFlac:
+		return "flac", codec
+	default:
+		return "aac", database.MediaCodecAac
+	}
+}
@@ -6,12 +6,17 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/govdbot/govd/internal/database"
 	"github.com/govdbot/govd/internal/models"
 	"github.com/govdbot/govd/internal/util/libav"
 )
 
 var MergeAudio = &models.Plugin{
-	ID: "merge_audio",
+	ID:    "merge_audio",
+	Stage: models.PluginStageMerge,
+
+	Inputs: []database.MediaType{database.MediaTypeVideo},
+
 	RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
 		filePath := format.FilePath
 
@@ -35,7 +40,10 @@ var MergeAudio = &models.Plugin{
 		) + "_remuxed" + filepath.Ext(filePath)
 		ctx.FilesTracker.Add(outputPath)
 
+		ctx.ReportProgress(models.ProgressPhaseMerging, 0, 0)
+
 		err = libav.MergeVideoWithAudio(
+			ctx.Context,
 			format.FilePath,
 			downloadedAudioFormat.FilePath,
 			outputPath,
@@ -0,0 +1,90 @@
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util/libav"
+)
+
+const reencodeID = "reencode"
+
+var x264Presets = []string{
+	"ultrafast", "superfast", "veryfast", "faster", "fast",
+	"medium", "slow", "slower", "veryslow",
+}
+
+// re-encodes videos to h264/aac, unlike transcode regardless
+// of their codecs, e.g. to reduce their size or resolution.
+// args: crf (quality, 0-51), preset (x264 preset)
+// and max_height (videos are scaled down to it).
+func newReencode(args map[string]string) (*models.Plugin, error) {
+	if err := checkArgs(args, "crf", "preset", "max_height"); err != nil {
+		return nil, err
+	}
+	crf, err := intArg(args, "crf", 23)
+	if err != nil {
+		return nil, err
+	}
+	if crf > 51 {
+		return nil, fmt.Errorf("invalid crf: %d", crf)
+	}
+	preset := "veryfast"
+	if value, ok := args["preset"]; ok {
+		if !slices.Contains(x264Presets, value) {
+			return nil, fmt.Errorf("invalid preset: %s", value)
+		}
+		preset = value
+	}
+	maxHeight, err := intArg(args, "max_height", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Plugin{
+		ID:    reencodeID,
+		Stage: models.PluginStageEncode,
+
+		Inputs: []database.MediaType{database.MediaTypeVideo},
+
+		RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
+			filePath := format.FilePath
+
+			outputPath := strings.TrimSuffix(
+				filePath,
+				filepath.Ext(filePath),
+			) + "_reencoded.mp4"
+			ctx.FilesTracker.Add(outputPath)
+
+			err := libav.Reencode(
+				ctx.Context, filePath, outputPath,
+				crf, preset, int32(maxHeight),
+				encodingProgress(ctx, format),
+			)
+			if err != nil {
+				return err
+			}
+			format.FilePath = outputPath
+
+			format.Format.VideoCodec = database.MediaCodecAvc
+			if format.Format.AudioCodec != "" {
+				format.Format.AudioCodec = database.MediaCodecAac
+			}
+			width, height, _ := libav.ExtractVideoMetadata(outputPath)
+			if width > 0 && height > 0 {
+				format.Format.Width = width
+				format.Format.Height = height
+			}
+			if info, err := os.Stat(outputPath); err == nil {
+				format.Format.FileSize = info.Size()
+			}
+
+			return nil
+		},
+	}, nil
+}
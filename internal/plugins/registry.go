@@ -0,0 +1,154 @@
+package plugins
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/govdbot/govd/internal/config"
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/logger"
+	"github.com/govdbot/govd/internal/models"
+)
+
+// builds a plugin from the arguments set in the config file
+type Factory func(args map[string]string) (*models.Plugin, error)
+
+var registry = map[string]Factory{
+	MergeAudio.ID:   static(MergeAudio),
+	ExtractAudio.ID: static(ExtractAudio),
+	Transcode.ID:    static(Transcode),
+	FitToLimit.ID:   static(FitToLimit),
+	ID3.ID:          static(ID3),
+	Tags.ID:         static(Tags),
+	loudnormID:      newLoudnorm,
+	reencodeID:      newReencode,
+	thumbnailID:     newThumbnail,
+}
+
+var mediaTypes = []database.MediaType{
+	database.MediaTypePhoto,
+	database.MediaTypeVideo,
+	database.MediaTypeAudio,
+}
+
+// plugins attached by the config file, keyed by extractor id
+var configured map[string][]*models.Plugin
+
+// builds the plugins attached by the config file.
+// must be called after the config is loaded.
+func Load() {
+	configured = make(map[string][]*models.Plugin)
+
+	for id, cfg := range config.GetExtractorConfigs() {
+		for _, pluginConfig := range cfg.Plugins {
+			plugin, err := build(pluginConfig)
+			if err != nil {
+				logger.L.Fatalf("[%s] invalid config: plugin %s: %v", id, pluginConfig.ID, err)
+			}
+			configured[id] = append(configured[id], plugin)
+		}
+	}
+}
+
+// returns the plugins attached by the config file to
+// the extractor, after the ones attached to every extractor
+func Configured(extractorID string) []*models.Plugin {
+	return slices.Concat(
+		configured[config.DefaultConfigKey],
+		configured[extractorID],
+	)
+}
+
+// returns the plugins of the chain ordered by stage.
+// plugins of the same stage keep their order, and the ones
+// attached more than once, e.g. both by the extractor and
+// by the config file, are only kept the first time.
+func Sort(chain []*models.Plugin) []*models.Plugin {
+	chain = slices.Clone(chain)
+	for i := len(chain) - 1; i > 0; i-- {
+		if slices.Index(chain, chain[i]) < i {
+			chain = slices.Delete(chain, i, i+1)
+		}
+	}
+	slices.SortStableFunc(chain, func(a, b *models.Plugin) int {
+		return int(a.Stage) - int(b.Stage)
+	})
+	return chain
+}
+
+func build(pluginConfig *config.PluginConfig) (*models.Plugin, error) {
+	factory, ok := registry[pluginConfig.ID]
+	if !ok {
+		return nil, fmt.Errorf("unknown plugin")
+	}
+	plugin, err := factory(pluginConfig.Args)
+	if err != nil {
+		return nil, err
+	}
+	if len(pluginConfig.MediaTypes) == 0 {
+		return plugin, nil
+	}
+
+	// restrict the plugin to the configured media types
+	var inputs []database.MediaType
+	for _, mediaType := range pluginConfig.MediaTypes {
+		mediaType := database.MediaType(mediaType)
+		if !slices.Contains(mediaTypes, mediaType) {
+			return nil, fmt.Errorf("unknown media type: %s", mediaType)
+		}
+		if plugin.Accepts(mediaType) {
+			inputs = append(inputs, mediaType)
+		}
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("does not accept any of the media types %v", pluginConfig.MediaTypes)
+	}
+	restricted := *plugin
+	restricted.Inputs = inputs
+	return &restricted, nil
+}
+
+// returns a factory for plugins that take no arguments
+func static(plugin *models.Plugin) Factory {
+	return func(args map[string]string) (*models.Plugin, error) {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("no arguments are supported")
+		}
+		return plugin, nil
+	}
+}
+
+// returns an error if any of the arguments is not one of the keys
+func checkArgs(args map[string]string, keys ...string) error {
+	for key := range args {
+		if !slices.Contains(keys, key) {
+			return fmt.Errorf("unknown argument: %s", key)
+		}
+	}
+	return nil
+}
+
+func floatArg(args map[string]string, key string, fallback float64) (float64, error) {
+	value, ok := args[key]
+	if !ok {
+		return fallback, nil
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return parsed, nil
+}
+
+func intArg(args map[string]string, key string, fallback int) (int, error) {
+	value, ok := args[key]
+	if !ok {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return parsed, nil
+}
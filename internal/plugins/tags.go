@@ -0,0 +1,177 @@
+package plugins
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util/libav"
+	"golang.org/x/image/draw"
+)
+
+// embeds title, artist and cover art into audio files,
+// in the way supported by their container: id3 frames for
+// mp3, ilst atoms for m4a and vorbis comments for flac/ogg.
+// opus is remuxed into ogg, so it gets vorbis comments too.
+var Tags = &models.Plugin{
+	ID:    "tags",
+	Stage: models.PluginStageMetadata,
+
+	Inputs: []database.MediaType{database.MediaTypeAudio},
+
+	RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
+		if format.FilePath == "" {
+			return fmt.Errorf("file path is empty")
+		}
+		if format.Format.Title == "" &&
+			format.Format.Artist == "" &&
+			format.ThumbnailFilePath == "" {
+			return nil
+		}
+
+		tags := make(map[string]string)
+		if format.Format.Title != "" {
+			tags["title"] = format.Format.Title
+		}
+		if format.Format.Artist != "" {
+			tags["artist"] = format.Format.Artist
+		}
+		var coverPath string
+
+		filePath := format.FilePath
+		ext := filepath.Ext(filePath)
+
+		switch format.Format.AudioCodec {
+		case database.MediaCodecMp3:
+			return ID3.RunFunc(ctx, item, format)
+		case database.MediaCodecAac, database.MediaCodecFlac:
+			coverPath = format.ThumbnailFilePath
+		case database.MediaCodecVorbis, database.MediaCodecOpus:
+			// opus usually comes in webm, which has no cover
+			// art, so it is remuxed to ogg like vorbis
+			if format.Format.AudioCodec == database.MediaCodecOpus {
+				ext = "." + string(models.FileExtensionOGG)
+			}
+			// ogg has no cover art stream, the picture
+			// is stored in a vorbis comment instead
+			if format.ThumbnailFilePath != "" {
+				picture, err := pictureBlock(format.ThumbnailFilePath)
+				if err != nil {
+					return err
+				}
+				tags["METADATA_BLOCK_PICTURE"] = picture
+			}
+		}
+		if len(tags) == 0 && coverPath == "" {
+			return nil
+		}
+
+		outputPath := strings.TrimSuffix(
+			filePath,
+			filepath.Ext(filePath),
+		) + "_tagged" + ext
+		ctx.FilesTracker.Add(outputPath)
+
+		err := libav.WriteTags(
+			ctx.Context, filePath, outputPath,
+			tags, coverPath,
+		)
+		if err != nil {
+			return err
+		}
+		format.FilePath = outputPath
+
+		if info, err := os.Stat(outputPath); err == nil {
+			format.Format.FileSize = info.Size()
+		}
+
+		return nil
+	},
+}
+
+// maximum size of the image in a picture block. the block is
+// passed to ffmpeg as an argument, which the kernel caps at
+// 128KB, and base64 grows the image by a third
+const maxPictureSize = 64 * 1024
+
+// returns the jpeg image as a base64 flac picture
+// block, the format of cover art in vorbis comments
+func pictureBlock(imagePath string) (string, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image file: %w", err)
+	}
+	if len(data) > maxPictureSize {
+		data, err = shrinkJPEG(data, maxPictureSize)
+		if err != nil {
+			return "", err
+		}
+	}
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	const (
+		mimeType    = "image/jpeg"
+		description = "Front Cover"
+		frontCover  = 3
+		colorDepth  = 24
+	)
+
+	var block bytes.Buffer
+	write := func(v uint32) {
+		binary.Write(&block, binary.BigEndian, v)
+	}
+	write(frontCover)
+	write(uint32(len(mimeType)))
+	block.WriteString(mimeType)
+	write(uint32(len(description)))
+	block.WriteString(description)
+	write(uint32(cfg.Width))
+	write(uint32(cfg.Height))
+	write(colorDepth)
+	write(0) // colors, only for indexed images
+	write(uint32(len(data)))
+	block.Write(data)
+
+	return base64.StdEncoding.EncodeToString(block.Bytes()), nil
+}
+
+// scales the jpeg image down until it fits the given size
+func shrinkJPEG(data []byte, maxSize int) ([]byte, error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	bounds := img.Bounds()
+	side := min(max(bounds.Dx(), bounds.Dy()), 1000)
+	for side >= 100 {
+		scale := float64(side) / float64(max(bounds.Dx(), bounds.Dy()))
+		dst := image.NewRGBA(image.Rect(
+			0, 0,
+			max(int(float64(bounds.Dx())*scale), 1),
+			max(int(float64(bounds.Dy())*scale), 1),
+		))
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+		var buf bytes.Buffer
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
+		if buf.Len() <= maxSize {
+			return buf.Bytes(), nil
+		}
+		side = side * 3 / 4
+	}
+	return nil, fmt.Errorf("failed to shrink image to %d bytes", maxSize)
+}
@@ -0,0 +1,97 @@
+package plugins
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util"
+	"github.com/govdbot/govd/internal/util/download"
+	"github.com/govdbot/govd/internal/util/libav"
+)
+
+const thumbnailID = "thumbnail"
+
+// replaces the thumbnail of the file, either with the image
+// at the given url or with the frame of the video at the given
+// position. args: url, or frame (a duration, e.g. 3s).
+func newThumbnail(args map[string]string) (*models.Plugin, error) {
+	if err := checkArgs(args, "url", "frame"); err != nil {
+		return nil, err
+	}
+	imageURL, hasURL := args["url"]
+	frame, hasFrame := args["frame"]
+	if hasURL == hasFrame {
+		return nil, fmt.Errorf("exactly one of url and frame is required")
+	}
+
+	inputs := []database.MediaType{database.MediaTypeVideo, database.MediaTypeAudio}
+	var position time.Duration
+	if hasURL {
+		parsed, err := url.Parse(imageURL)
+		if err != nil || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid url: %s", imageURL)
+		}
+	} else {
+		var err error
+		position, err = time.ParseDuration(frame)
+		if err != nil || position < 0 {
+			return nil, fmt.Errorf("invalid frame: %s", frame)
+		}
+		// only videos have frames
+		inputs = []database.MediaType{database.MediaTypeVideo}
+	}
+
+	return &models.Plugin{
+		ID:    thumbnailID,
+		Stage: models.PluginStageThumbnail,
+
+		Inputs: inputs,
+
+		RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
+			thumbnailFilePath := format.ThumbnailFilePath
+			if thumbnailFilePath == "" {
+				thumbnailFilePath = strings.TrimSuffix(
+					format.FilePath,
+					filepath.Ext(format.FilePath),
+				) + ".jpeg"
+				ctx.FilesTracker.Add(thumbnailFilePath)
+			}
+
+			if !hasURL {
+				err := libav.ExtractVideoFrame(
+					ctx.Context, format.FilePath,
+					thumbnailFilePath, position,
+				)
+				if err != nil {
+					return fmt.Errorf("failed to extract frame: %w", err)
+				}
+				format.ThumbnailFilePath = thumbnailFilePath
+				return nil
+			}
+
+			file, err := download.DownloadFileInMemory(
+				ctx, []string{imageURL}, nil,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to download thumbnail: %w", err)
+			}
+			var size int
+			if format.Format.Type == database.MediaTypeAudio {
+				// telegram expects smaller thumbnails for audio
+				size = 320
+			}
+			_, err = util.ImgToJPEG(file, thumbnailFilePath, size)
+			if err != nil {
+				return fmt.Errorf("failed to convert thumbnail: %w", err)
+			}
+			format.ThumbnailFilePath = thumbnailFilePath
+
+			return nil
+		},
+	}, nil
+}
@@ -0,0 +1,63 @@
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/govdbot/govd/internal/database"
+	"github.com/govdbot/govd/internal/models"
+	"github.com/govdbot/govd/internal/util/libav"
+)
+
+var Transcode = &models.Plugin{
+	ID:    "transcode",
+	Stage: models.PluginStageEncode,
+
+	Inputs: []database.MediaType{database.MediaTypeVideo},
+
+	RunFunc: func(ctx *models.ExtractorContext, item *models.MediaItem, format *models.DownloadedFormat) error {
+		filePath := format.FilePath
+
+		outputPath := strings.TrimSuffix(
+			filePath,
+			filepath.Ext(filePath),
+		) + "_transcoded.mp4"
+		ctx.FilesTracker.Add(outputPath)
+
+		err := libav.TranscodeToMP4(
+			ctx.Context, filePath, outputPath,
+			encodingProgress(ctx, format),
+		)
+		if err != nil {
+			return err
+		}
+		format.FilePath = outputPath
+
+		format.Format.VideoCodec = database.MediaCodecAvc
+		if format.Format.AudioCodec != "" {
+			format.Format.AudioCodec = database.MediaCodecAac
+		}
+		if info, err := os.Stat(outputPath); err == nil {
+			format.Format.FileSize = info.Size()
+		}
+
+		return nil
+	},
+}
+
+// reports the encoding progress of the
+// format, in seconds of processed output
+func encodingProgress(
+	ctx *models.ExtractorContext,
+	format *models.DownloadedFormat,
+) func(time.Duration) {
+	total := int64(format.Format.Duration)
+	return func(processed time.Duration) {
+		ctx.ReportProgress(
+			models.ProgressPhaseProcessing,
+			int64(processed.Seconds()), total,
+		)
+	}
+}
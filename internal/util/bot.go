@@ -86,6 +86,8 @@ func ChatFromContext(ctx *ext.Context) (*database.GetOrCreateChatRow, error) {
 			Nsfw:            config.Env.DefaultNSFW,
 			MediaAlbumLimit: config.Env.DefaultMediaAlbumLimit,
 			DeleteLinks:     config.Env.DefaultDeleteLinks,
+			FormatPicker:    config.Env.DefaultFormatPicker,
+			VideoQuality:    config.Env.DefaultVideoQuality,
 		},
 	)
 	if err != nil {
@@ -194,12 +196,13 @@ func CheckAdminPermission(b *gotgbot.Bot, ctx *ext.Context, localizer *localizat
 
 func HasHashtagEntity(msg *gotgbot.Message, entity string) bool {
 	entity = "#" + entity
-	for _, ent := range msg.Entities {
+	text, entities := messageEntities(msg)
+	for _, ent := range entities {
 		if ent.Type != "hashtag" {
 			continue
 		}
 		parsedEntity := gotgbot.ParseEntity(
-			msg.Text,
+			text,
 			ent,
 		)
 		if parsedEntity.Text == entity {
@@ -210,17 +213,43 @@ func HasHashtagEntity(msg *gotgbot.Message, entity string) bool {
 }
 
 func URLFromMessage(msg *gotgbot.Message) string {
-	for _, entity := range msg.Entities {
-		if entity.Type != "url" {
+	urls := URLsFromMessage(msg)
+	if len(urls) == 0 {
+		return ""
+	}
+	return urls[0]
+}
+
+// returns every url of the message, in order and without
+// duplicates, including text links and urls in captions
+func URLsFromMessage(msg *gotgbot.Message) []string {
+	text, entities := messageEntities(msg)
+
+	var urls []string
+	for _, entity := range entities {
+		var url string
+		switch entity.Type {
+		case "url":
+			url = gotgbot.ParseEntity(text, entity).Text
+		case "text_link":
+			url = entity.Url
+		default:
 			continue
 		}
-		parsedEntity := gotgbot.ParseEntity(
-			msg.Text,
-			entity,
-		)
-		return parsedEntity.Text
+		if url != "" && !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// returns the text of the message and its
+// entities, falling back to the media caption
+func messageEntities(msg *gotgbot.Message) (string, []gotgbot.MessageEntity) {
+	if msg.Text != "" {
+		return msg.Text, msg.Entities
 	}
-	return ""
+	return msg.Caption, msg.CaptionEntities
 }
 
 func ExtractCommentFromMessage(msg *gotgbot.Message) string {
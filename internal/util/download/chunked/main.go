@@ -139,7 +139,11 @@ func (cd *ChunkedDownloader) Download(
 		close(chunks)
 	}()
 
-	return cd.writeChunks(writer, chunks)
+	progress := models.NewProgressWriter(
+		writer, cd.totalSize,
+		ctx.PhaseProgress(models.ProgressPhaseDownloading),
+	)
+	return cd.writeChunks(progress, chunks)
 }
 
 func (cd *ChunkedDownloader) downloadChunk(
@@ -66,7 +66,7 @@ func DownloadFile(
 		) + "_remuxed" + filepath.Ext(filePath)
 		ctx.FilesTracker.Add(outputPath)
 
-		err = libav.RemuxFile(filePath, outputPath)
+		err = libav.RemuxFile(ctx.Context, filePath, outputPath)
 		if err != nil {
 			ctx.Warnf("remuxing failed, using original file: %v", err)
 			return filePath, nil
@@ -114,6 +114,7 @@ func DownloadFileWithSegments(
 		&segmented.SegmentedDownloaderOptions{
 			InitSegment:      initSegmentURL,
 			DownloadSettings: settings,
+			OnProgress:       ctx.PhaseProgress(models.ProgressPhaseDownloading),
 		},
 	)
 
@@ -134,7 +135,7 @@ func DownloadFileWithSegments(
 	) + "_remuxed" + filepath.Ext(filePath)
 	ctx.FilesTracker.Add(outputPath)
 
-	err = libav.RemuxFile(filePath, outputPath)
+	err = libav.RemuxFile(ctx.Context, filePath, outputPath)
 	if err != nil {
 		ctx.Warnf("remuxing failed, using original file: %v", err)
 		return filePath, nil
@@ -219,7 +220,12 @@ func downloadSequential(
 		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
-	_, err = io.Copy(writer, resp.Body)
+	// content length is -1 when unknown
+	progress := models.NewProgressWriter(
+		writer, max(resp.ContentLength, 0),
+		ctx.PhaseProgress(models.ProgressPhaseDownloading),
+	)
+	_, err = io.Copy(progress, resp.Body)
 	if err != nil {
 		return err
 	}
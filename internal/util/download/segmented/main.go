@@ -19,6 +19,7 @@ type SegmentedDownloader struct {
 	initSegment      string
 	segments         []string
 	downloadSettings *models.DownloadSettings
+	onProgress       func(current int64, total int64)
 
 	wg sync.WaitGroup
 }
@@ -26,11 +27,16 @@ type SegmentedDownloader struct {
 type SegmentedDownloaderOptions struct {
 	InitSegment      string
 	DownloadSettings *models.DownloadSettings
+
+	// called every time a segment completes, with the
+	// downloaded bytes and an estimate of the total size
+	OnProgress func(current int64, total int64)
 }
 
 type Segment struct {
 	index    int
 	filePath string
+	size     int64
 	err      error
 }
 
@@ -51,6 +57,7 @@ func New(
 		initSegment:      options.InitSegment,
 		segments:         segments,
 		downloadSettings: options.DownloadSettings,
+		onProgress:       options.OnProgress,
 	}
 }
 
@@ -95,7 +102,7 @@ func (sd *SegmentedDownloader) Download(
 	var initSegmentPath string
 	if sd.initSegment != "" {
 		initSegmentPath = filepath.Join(sd.path, "init_segment")
-		err := sd.downloadSegmentToFile(ctx, sd.initSegment, initSegmentPath)
+		_, err := sd.downloadSegmentToFile(ctx, sd.initSegment, initSegmentPath)
 		if err != nil {
 			return fmt.Errorf("failed to download init segment: %w", err)
 		}
@@ -113,11 +120,22 @@ func (sd *SegmentedDownloader) Download(
 
 func (sd *SegmentedDownloader) collectSegments(segments <-chan *Segment) ([]string, error) {
 	collected := make([]string, len(sd.segments))
+	var downloaded int64
+	var completed int64
 	for seg := range segments {
 		if seg.err != nil {
 			return nil, fmt.Errorf("failed to download segment %d: %w", seg.index, seg.err)
 		}
 		collected[seg.index] = seg.filePath
+
+		downloaded += seg.size
+		completed++
+		if sd.onProgress != nil {
+			// segments have similar sizes, so the total
+			// is estimated from the average segment size
+			total := downloaded * int64(len(sd.segments)) / completed
+			sd.onProgress(downloaded, total)
+		}
 	}
 	return collected, nil
 }
@@ -131,20 +149,20 @@ func (sd *SegmentedDownloader) downloadSegment(
 	segmentFileName := fmt.Sprintf("segment_%05d", index)
 	segmentFilePath := filepath.Join(sd.path, segmentFileName)
 
-	err := sd.downloadSegmentToFile(ctx, url, segmentFilePath)
+	size, err := sd.downloadSegmentToFile(ctx, url, segmentFilePath)
 	if err != nil {
 		segments <- &Segment{index: index, err: err}
 		return
 	}
 
-	segments <- &Segment{index: index, filePath: segmentFilePath, err: nil}
+	segments <- &Segment{index: index, filePath: segmentFilePath, size: size, err: nil}
 }
 
 func (sd *SegmentedDownloader) downloadSegmentToFile(
 	ctx context.Context,
 	url string,
 	filePath string,
-) error {
+) (int64, error) {
 	maxRetries := max(sd.downloadSettings.Retries, 1)
 	var lastErr error
 
@@ -170,10 +188,10 @@ func (sd *SegmentedDownloader) downloadSegmentToFile(
 		file, err := os.Create(filePath)
 		if err != nil {
 			resp.Body.Close()
-			return fmt.Errorf("failed to create file: %w", err)
+			return 0, fmt.Errorf("failed to create file: %w", err)
 		}
 
-		_, err = io.Copy(file, resp.Body)
+		size, err := io.Copy(file, resp.Body)
 		resp.Body.Close()
 		file.Close()
 
@@ -182,8 +200,8 @@ func (sd *SegmentedDownloader) downloadSegmentToFile(
 			continue
 		}
 
-		return nil
+		return size, nil
 	}
 
-	return lastErr
+	return 0, lastErr
 }
@@ -32,6 +32,8 @@ var (
 	ErrDurationTooLong               = &Error{ID: localization.ErrorDurationTooLong.ID}
 	ErrPaidContent                   = &Error{ID: localization.ErrorPaidContent.ID}
 	ErrAgeRestricted                 = &Error{ID: localization.ErrorAgeRestricted.ID}
+	ErrCollectionUnsupported         = &Error{ID: localization.ErrorCollectionUnsupported.ID}
+	ErrCollectionLimitExceeded       = &Error{ID: localization.ErrorCollectionLimitExceeded.ID}
 )
 
 func HashedError(err error) string {
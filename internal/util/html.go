@@ -0,0 +1,215 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// length limits of captions and messages, in utf-16
+	// code units of the text left after parsing the html
+	TelegramMaxCaptionLength = 1024
+	TelegramMaxMessageLength = 4096
+)
+
+// tags supported by telegram, with their allowed attributes
+var telegramTags = map[string][]string{
+	"b":          nil,
+	"strong":     nil,
+	"i":          nil,
+	"em":         nil,
+	"u":          nil,
+	"ins":        nil,
+	"s":          nil,
+	"strike":     nil,
+	"del":        nil,
+	"tg-spoiler": nil,
+	"span":       {"class"},
+	"a":          {"href"},
+	"tg-emoji":   {"emoji-id"},
+	"code":       {"class"},
+	"pre":        nil,
+	"blockquote": {"expandable"},
+}
+
+// the only named entities supported by telegram
+var entityPattern = regexp.MustCompile(`&(?:lt|gt|amp|quot|#\d+|#x[0-9a-fA-F]+);`)
+
+// reports whether the text can be sent with the html parse mode:
+// only the tags and entities supported by telegram are used,
+// and every tag is closed in the right order.
+func ValidateTelegramHTML(text string) error {
+	tokenizer := html.NewTokenizer(strings.NewReader(text))
+	var open []string
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if !errors.Is(tokenizer.Err(), io.EOF) {
+				return tokenizer.Err()
+			}
+			if len(open) > 0 {
+				return fmt.Errorf("unclosed tag: <%s>", open[len(open)-1])
+			}
+			return nil
+		case html.TextToken:
+			raw := string(tokenizer.Raw())
+			if strings.ContainsAny(raw, "<>") {
+				return fmt.Errorf("unescaped < or > in text")
+			}
+			unknown := strings.Count(raw, "&") - len(entityPattern.FindAllString(raw, -1))
+			if unknown > 0 {
+				return fmt.Errorf("unescaped & or unsupported entity in text")
+			}
+		case html.StartTagToken:
+			token := tokenizer.Token()
+			attributes, ok := telegramTags[token.Data]
+			if !ok {
+				return fmt.Errorf("unsupported tag: <%s>", token.Data)
+			}
+			for _, attr := range token.Attr {
+				if !slices.Contains(attributes, attr.Key) {
+					return fmt.Errorf("unsupported attribute of <%s>: %s", token.Data, attr.Key)
+				}
+				if token.Data == "span" && attr.Val != "tg-spoiler" {
+					return fmt.Errorf("unsupported class of <span>: %s", attr.Val)
+				}
+			}
+			open = append(open, token.Data)
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			if len(open) == 0 || open[len(open)-1] != token.Data {
+				return fmt.Errorf("unexpected closing tag: </%s>", token.Data)
+			}
+			open = open[:len(open)-1]
+		default:
+			return fmt.Errorf("unsupported html: %s", tokenizer.Raw())
+		}
+	}
+}
+
+// escapes the text to be used with the html parse mode,
+// dropping invalid utf-8 and the control characters
+func EscapeHTML(text string) string {
+	text = strings.ToValidUTF8(text, "")
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return -1
+		}
+		return r
+	}, text)
+	return html.EscapeString(text)
+}
+
+// returns the length of the html text as telegram measures it:
+// utf-16 code units of the text, without tags and with entities
+// counted as the character they stand for
+func TelegramLength(text string) int {
+	tokenizer := html.NewTokenizer(strings.NewReader(text))
+	var n int
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return n
+		case html.TextToken:
+			n += utf16Length(string(tokenizer.Text()))
+		}
+	}
+}
+
+// cuts the html text to the given length, as returned by
+// TelegramLength, at the last word boundary before it and
+// ending with an ellipsis. the tags open at the cut are closed,
+// and the text that didn't fit is returned with them reopened,
+// so that both parts are valid html on their own.
+func TruncateHTML(text string, limit int) (string, string) {
+	if TelegramLength(text) <= limit {
+		return text, ""
+	}
+	// room for the ellipsis
+	limit--
+
+	type openTag struct {
+		name string
+		raw  string
+	}
+	var open []openTag
+	var head strings.Builder
+	var length, offset int
+
+	tokenizer := html.NewTokenizer(strings.NewReader(text))
+	for {
+		tokenType := tokenizer.Next()
+		if tokenType == html.ErrorToken {
+			return head.String(), ""
+		}
+		raw := string(tokenizer.Raw())
+		offset += len(raw)
+
+		switch tokenType {
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			open = append(open, openTag{string(name), raw})
+		case html.EndTagToken:
+			if len(open) > 0 {
+				open = open[:len(open)-1]
+			}
+		case html.TextToken:
+			content := html.UnescapeString(raw)
+			n := utf16Length(content)
+			if length+n <= limit {
+				length += n
+				break
+			}
+			cut := cutIndex(content, limit-length)
+			head.WriteString(EscapeHTML(strings.TrimRightFunc(content[:cut], unicode.IsSpace)))
+			head.WriteString("…")
+			for i := len(open) - 1; i >= 0; i-- {
+				head.WriteString("</" + open[i].name + ">")
+			}
+			var rest strings.Builder
+			for _, tag := range open {
+				rest.WriteString(tag.raw)
+			}
+			rest.WriteString(EscapeHTML(strings.TrimLeftFunc(content[cut:], unicode.IsSpace)))
+			rest.WriteString(text[offset:])
+			return head.String(), rest.String()
+		}
+		head.WriteString(raw)
+	}
+}
+
+// returns the byte index where the text has to be cut to fit
+// the given utf-16 length, preferring the last whitespace before it
+func cutIndex(text string, length int) int {
+	var n int
+	for i, r := range text {
+		n += utf16.RuneLen(r)
+		if n <= length {
+			continue
+		}
+		if unicode.IsSpace(r) {
+			return i
+		}
+		if j := strings.LastIndexFunc(text[:i], unicode.IsSpace); j > 0 {
+			return j
+		}
+		return i
+	}
+	return len(text)
+}
+
+func utf16Length(text string) int {
+	var n int
+	for _, r := range text {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
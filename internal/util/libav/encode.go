@@ -0,0 +1,102 @@
+package libav
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/govdbot/govd/internal/logger"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// re-encodes the input file to an h264/aac mp4 whose
+// bitrate does not exceed the given ones (in bits per second).
+// when width and height are set, the video is scaled to them.
+// onProgress, if set, receives the duration processed so far.
+func EncodeWithBitrate(
+	ctx context.Context,
+	inputPath string,
+	outputPath string,
+	videoBitrate int64,
+	audioBitrate int64,
+	width int32,
+	height int32,
+	onProgress func(processed time.Duration),
+) error {
+	logger.L.Debugf(
+		"encoding file with bitrate %d/%d: %s",
+		videoBitrate, audioBitrate, inputPath,
+	)
+
+	kwArgs := ffmpeg.KwArgs{
+		"map":      []string{"0:v:0", "0:a:0?"},
+		"c:v":      "libx264",
+		"preset":   "veryfast",
+		"crf":      23,
+		"maxrate":  fmt.Sprintf("%dk", videoBitrate/1000),
+		"bufsize":  fmt.Sprintf("%dk", videoBitrate/1000),
+		"pix_fmt":  "yuv420p",
+		"c:a":      "aac",
+		"b:a":      fmt.Sprintf("%dk", audioBitrate/1000),
+		"movflags": "+faststart",
+	}
+	if width > 0 && height > 0 {
+		kwArgs["vf"] = fmt.Sprintf("scale=%d:%d", width, height)
+	}
+
+	stream := ffmpeg.Input(inputPath).
+		Output(outputPath, kwArgs)
+
+	err := runStream(ctx, stream, onProgress)
+
+	if err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf("failed to encode file: %w", err)
+	}
+
+	return nil
+}
+
+// re-encodes the input file to an h264/aac mp4 with the given
+// quality (crf) and x264 preset. when maxHeight is set, taller
+// videos are scaled down to it, keeping the aspect ratio.
+// onProgress, if set, receives the duration processed so far.
+func Reencode(
+	ctx context.Context,
+	inputPath string,
+	outputPath string,
+	crf int,
+	preset string,
+	maxHeight int32,
+	onProgress func(processed time.Duration),
+) error {
+	logger.L.Debugf("re-encoding file with crf %d: %s", crf, inputPath)
+
+	scale := "scale=trunc(iw/2)*2:trunc(ih/2)*2"
+	if maxHeight > 0 {
+		scale = fmt.Sprintf("scale=-2:'min(%d,trunc(ih/2)*2)'", maxHeight)
+	}
+
+	stream := ffmpeg.Input(inputPath).
+		Output(outputPath, ffmpeg.KwArgs{
+			"map":      []string{"0:v:0", "0:a:0?"},
+			"c:v":      "libx264",
+			"preset":   preset,
+			"crf":      crf,
+			"pix_fmt":  "yuv420p",
+			"vf":       scale,
+			"c:a":      "aac",
+			"b:a":      "128k",
+			"movflags": "+faststart",
+		})
+
+	err := runStream(ctx, stream, onProgress)
+
+	if err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf("failed to re-encode file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package libav
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/govdbot/govd/internal/logger"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// extracts the first audio stream of the input file.
+// the stream is copied as it is, unless transcode is
+// set, in which case it is re-encoded to mp3.
+func ExtractAudio(
+	ctx context.Context,
+	inputPath string,
+	outputPath string,
+	transcode bool,
+) error {
+	logger.L.Debugf("extracting audio from file: %s", inputPath)
+
+	kwArgs := ffmpeg.KwArgs{
+		"map": "0:a:0",
+		"vn":  "",
+	}
+	if transcode {
+		kwArgs["c:a"] = "libmp3lame"
+		kwArgs["b:a"] = "192k"
+	} else {
+		kwArgs["c:a"] = "copy"
+		// only the mp4 muxer (m4a) knows this option
+		if strings.EqualFold(filepath.Ext(outputPath), ".m4a") {
+			kwArgs["movflags"] = "+faststart"
+		}
+	}
+
+	stream := ffmpeg.Input(inputPath).
+		Output(outputPath, kwArgs)
+
+	err := runStream(ctx, stream, nil)
+
+	if err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf("failed to extract audio: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package libav
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/govdbot/govd/internal/logger"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// normalizes the loudness of the first audio stream of the input
+// file to the given integrated loudness (LUFS), true peak (dBTP)
+// and loudness range (LU). the video stream, if any, is copied,
+// while the audio is re-encoded with the given ffmpeg encoder.
+// onProgress, if set, receives the duration processed so far.
+func NormalizeLoudness(
+	ctx context.Context,
+	inputPath string,
+	outputPath string,
+	integrated float64,
+	truePeak float64,
+	loudnessRange float64,
+	audioEncoder string,
+	onProgress func(processed time.Duration),
+) error {
+	logger.L.Debugf("normalizing loudness of file: %s", inputPath)
+
+	stream := ffmpeg.Input(inputPath).
+		Output(outputPath, ffmpeg.KwArgs{
+			"map": []string{"0:v:0?", "0:a:0"},
+			"c:v": "copy",
+			"c:a": audioEncoder,
+			"af": fmt.Sprintf(
+				"loudnorm=I=%g:TP=%g:LRA=%g",
+				integrated, truePeak, loudnessRange,
+			),
+			"movflags": "+faststart",
+		})
+
+	err := runStream(ctx, stream, onProgress)
+
+	if err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf("failed to normalize loudness: %w", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package libav
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -8,11 +9,12 @@ import (
 )
 
 func MergeVideoWithAudio(
+	ctx context.Context,
 	videoPath string,
 	audioPath string,
 	outputPath string,
 ) error {
-	err := ffmpeg.Output(
+	stream := ffmpeg.Output(
 		[]*ffmpeg.Stream{
 			ffmpeg.Input(videoPath),
 			ffmpeg.Input(audioPath),
@@ -22,10 +24,9 @@ func MergeVideoWithAudio(
 			"movflags": "+faststart",
 			"c:v":      "copy",
 			"c:a":      "copy",
-		}).
-		Silent(true).
-		OverWriteOutput().
-		Run()
+		})
+
+	err := runStream(ctx, stream, nil)
 
 	if err != nil {
 		os.Remove(outputPath)
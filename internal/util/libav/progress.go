@@ -0,0 +1,38 @@
+package libav
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// parses the output of the ffmpeg -progress option,
+// reporting the duration of the processed output
+type progressWriter struct {
+	buffer     []byte
+	onProgress func(processed time.Duration)
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+	for {
+		index := bytes.IndexByte(w.buffer, '\n')
+		if index < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(w.buffer[:index]))
+		w.buffer = w.buffer[index+1:]
+
+		value, ok := strings.CutPrefix(line, "out_time_us=")
+		if !ok {
+			continue
+		}
+		us, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || us < 0 {
+			continue
+		}
+		w.onProgress(time.Duration(us) * time.Microsecond)
+	}
+	return len(p), nil
+}
@@ -1,6 +1,7 @@
 package libav
 
 import (
+	"context"
 	"os"
 
 	"github.com/govdbot/govd/internal/extractors/twitter"
@@ -8,23 +9,22 @@ import (
 	ffmpeg "github.com/u2takey/ffmpeg-go"
 )
 
-func RemuxFile(inputPath string, outputPath string) error {
+func RemuxFile(ctx context.Context, inputPath string, outputPath string) error {
 	isVork := twitter.IsVorkMuxer(inputPath)
 	if isVork {
-		return RemuxFileWithDoublePass(inputPath, outputPath)
+		return RemuxFileWithDoublePass(ctx, inputPath, outputPath)
 	}
 
 	logger.L.Debugf("remuxing file: %s", inputPath)
 
-	err := ffmpeg.Input(inputPath).
+	stream := ffmpeg.Input(inputPath).
 		Output(outputPath, ffmpeg.KwArgs{
 			"map":      "0",
 			"c":        "copy",
 			"movflags": "+faststart",
-		}).
-		Silent(true).
-		OverWriteOutput().
-		Run()
+		})
+
+	err := runStream(ctx, stream, nil)
 
 	if err != nil {
 		os.Remove(outputPath)
@@ -33,34 +33,32 @@ func RemuxFile(inputPath string, outputPath string) error {
 	return nil
 }
 
-func RemuxFileWithDoublePass(inputPath string, outputPath string) error {
+func RemuxFileWithDoublePass(ctx context.Context, inputPath string, outputPath string) error {
 	logger.L.Debugf("remuxing file with double pass: %s", inputPath)
 
 	tempPath := inputPath + ".temp.mkv"
-	err := ffmpeg.Input(inputPath).
+	stream := ffmpeg.Input(inputPath).
 		Output(tempPath, ffmpeg.KwArgs{
 			"map":      "0",
 			"c":        "copy",
 			"movflags": "+faststart",
-		}).
-		Silent(true).
-		OverWriteOutput().
-		Run()
+		})
+
+	err := runStream(ctx, stream, nil)
 
 	if err != nil {
 		os.Remove(tempPath)
 		return err
 	}
 
-	err = ffmpeg.Input(tempPath).
+	stream = ffmpeg.Input(tempPath).
 		Output(outputPath, ffmpeg.KwArgs{
 			"map":      "0",
 			"c":        "copy",
 			"movflags": "+faststart",
-		}).
-		Silent(true).
-		OverWriteOutput().
-		Run()
+		})
+
+	err = runStream(ctx, stream, nil)
 
 	os.Remove(tempPath)
 
@@ -0,0 +1,31 @@
+package libav
+
+import (
+	"context"
+	"time"
+
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// runs the ffmpeg stream, killing the process as soon
+// as ctx is done. onProgress, if set, receives the
+// duration of the output processed so far.
+func runStream(
+	ctx context.Context,
+	stream *ffmpeg.Stream,
+	onProgress func(processed time.Duration),
+) error {
+	if onProgress != nil {
+		stream = stream.GlobalArgs("-progress", "pipe:1", "-nostats")
+	}
+	// must be set before any other option,
+	// as those are stored in the context itself
+	stream.Context = ctx
+	if onProgress != nil {
+		stream = stream.WithOutput(&progressWriter{onProgress: onProgress})
+	}
+	return stream.
+		Silent(true).
+		OverWriteOutput().
+		Run()
+}
@@ -0,0 +1,54 @@
+package libav
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/govdbot/govd/internal/logger"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// writes the metadata tags into the audio file, copying its
+// stream. when coverPath is set, the image is attached as the
+// cover art, which is supported by mp4 and flac containers.
+// the output container is chosen from the extension of outputPath.
+func WriteTags(
+	ctx context.Context,
+	inputPath string,
+	outputPath string,
+	tags map[string]string,
+	coverPath string,
+) error {
+	logger.L.Debugf("writing tags to file: %s", inputPath)
+
+	metadata := make([]string, 0, len(tags))
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		metadata = append(metadata, key+"="+tags[key])
+	}
+
+	// streams are mapped by their selectors, as
+	// ffmpeg-go maps whole inputs otherwise
+	inputs := []*ffmpeg.Stream{ffmpeg.Input(inputPath).Get("a:0")}
+	kwArgs := ffmpeg.KwArgs{
+		"c":        "copy",
+		"metadata": metadata,
+	}
+	if coverPath != "" {
+		inputs = append(inputs, ffmpeg.Input(coverPath).Get("v:0"))
+		kwArgs["disposition:v:0"] = "attached_pic"
+	}
+
+	stream := ffmpeg.Output(inputs, outputPath, kwArgs)
+
+	err := runStream(ctx, stream, nil)
+
+	if err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf("failed to write tags: %w", err)
+	}
+
+	return nil
+}
@@ -1,27 +1,30 @@
 package libav
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/govdbot/govd/internal/logger"
 	ffmpeg "github.com/u2takey/ffmpeg-go"
 )
 
 func ExtractVideoThumbnail(
+	ctx context.Context,
 	videoPath string,
 	outputPath string,
 ) (string, error) {
 	logger.L.Debugf("extracting thumbnail from video: %s", videoPath)
 
-	err := ffmpeg.Input(videoPath).
+	stream := ffmpeg.Input(videoPath).
 		Filter("select", ffmpeg.Args{"gte(n,0)"}).
 		Output(outputPath, ffmpeg.KwArgs{
 			"vframes": 1,
 			"vcodec":  "mjpeg",
-		}).
-		Silent(true).
-		OverWriteOutput().
-		Run()
+		})
+
+	err := runStream(ctx, stream, nil)
 
 	if err != nil {
 		os.Remove(outputPath)
@@ -30,3 +33,33 @@ func ExtractVideoThumbnail(
 
 	return outputPath, nil
 }
+
+// extracts the frame of the video at the given position.
+// the first frame is used when the video is shorter.
+func ExtractVideoFrame(
+	ctx context.Context,
+	videoPath string,
+	outputPath string,
+	position time.Duration,
+) error {
+	logger.L.Debugf("extracting frame at %s from video: %s", position, videoPath)
+
+	stream := ffmpeg.Input(videoPath, ffmpeg.KwArgs{
+		"ss": fmt.Sprintf("%.3f", position.Seconds()),
+	}).Output(outputPath, ffmpeg.KwArgs{
+		"vframes": 1,
+		"vcodec":  "mjpeg",
+	})
+
+	err := runStream(ctx, stream, nil)
+	if err == nil {
+		if info, statErr := os.Stat(outputPath); statErr == nil && info.Size() > 0 {
+			return nil
+		}
+	}
+	os.Remove(outputPath)
+
+	// seeking past the end produces no frame
+	_, err = ExtractVideoThumbnail(ctx, videoPath, outputPath)
+	return err
+}
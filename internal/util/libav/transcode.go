@@ -0,0 +1,45 @@
+package libav
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/govdbot/govd/internal/logger"
+	ffmpeg "github.com/u2takey/ffmpeg-go"
+)
+
+// re-encodes the input file to an h264/aac mp4,
+// which can be played and streamed by telegram clients.
+// onProgress, if set, receives the duration processed so far.
+func TranscodeToMP4(
+	ctx context.Context,
+	inputPath string,
+	outputPath string,
+	onProgress func(processed time.Duration),
+) error {
+	logger.L.Debugf("transcoding file: %s", inputPath)
+
+	stream := ffmpeg.Input(inputPath).
+		Output(outputPath, ffmpeg.KwArgs{
+			"map":      []string{"0:v:0", "0:a:0?"},
+			"c:v":      "libx264",
+			"preset":   "veryfast",
+			"crf":      23,
+			"pix_fmt":  "yuv420p",
+			"vf":       "scale=trunc(iw/2)*2:trunc(ih/2)*2",
+			"c:a":      "aac",
+			"b:a":      "128k",
+			"movflags": "+faststart",
+		})
+
+	err := runStream(ctx, stream, onProgress)
+
+	if err != nil {
+		os.Remove(outputPath)
+		return fmt.Errorf("failed to transcode file: %w", err)
+	}
+
+	return nil
+}
@@ -18,6 +18,14 @@ import (
 	"golang.org/x/net/publicsuffix"
 )
 
+const (
+	// maximum size of files uploaded through the bot api
+	TelegramMaxFileSize = 50 * 1024 * 1024 // 50MB
+	// maximum size of files uploaded through a local
+	// bot api server, which reads them from the disk
+	TelegramLocalMaxFileSize = 2000 * 1024 * 1024 // 2000MB
+)
+
 func GetNamedGroups(re *regexp.Regexp, str string) map[string]string {
 	match := re.FindStringSubmatch(str)
 	names := re.SubexpNames()
@@ -52,6 +60,25 @@ func ExceedsMaxFileSize(fileSize int64) bool {
 	return fileSize > config.Env.MaxFileSize
 }
 
+// returns the maximum size of files telegram accepts,
+// which depends on how the bot api receives them
+func TelegramFileSizeLimit() int64 {
+	if config.Env.BotAPILocal {
+		return TelegramLocalMaxFileSize
+	}
+	return TelegramMaxFileSize
+}
+
+func ExceedsTelegramFileSize(fileSize int64) bool {
+	return fileSize > TelegramFileSizeLimit()
+}
+
+// returns the maximum size of files that can be sent,
+// considering both the instance and telegram limits
+func MaxUploadSize() int64 {
+	return min(config.Env.MaxFileSize, TelegramFileSizeLimit())
+}
+
 func ExceedsMaxDuration(duration int32) bool {
 	return duration > int32(config.Env.MaxDuration.Seconds())
 }